@@ -0,0 +1,368 @@
+package goes
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// bucketFields are the fields of a bucket which are not sub-aggregations
+var bucketFields = map[string]bool{
+	"key":            true,
+	"key_as_string":  true,
+	"doc_count":      true,
+	"from":           true,
+	"from_as_string": true,
+	"to":             true,
+	"to_as_string":   true,
+}
+
+// get returns the aggregation of the given name
+func (a Aggregations) get(name string) (Aggregation, error) {
+	agg, ok := a[name]
+	if !ok {
+		return nil, fmt.Errorf("Aggregation %q not found", name)
+	}
+	return agg, nil
+}
+
+// Terms returns the buckets of the terms aggregation of the given name
+func (a Aggregations) Terms(name string) (*BucketAggregation, error) {
+	return a.Buckets(name)
+}
+
+// DateHistogram returns the buckets of the date_histogram aggregation of the
+// given name. AggregationBucket.Time gives the date of a bucket.
+func (a Aggregations) DateHistogram(name string) (*BucketAggregation, error) {
+	return a.Buckets(name)
+}
+
+// Histogram returns the buckets of the histogram aggregation of the given name
+func (a Aggregations) Histogram(name string) (*BucketAggregation, error) {
+	return a.Buckets(name)
+}
+
+// Buckets returns the buckets of the multi-bucket aggregation of the given
+// name. The buckets of keyed aggregations are ordered by key.
+func (a Aggregations) Buckets(name string) (*BucketAggregation, error) {
+	agg, err := a.get(name)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &BucketAggregation{}
+	if result.DocCountErrorUpperBound, err = intField(agg, "doc_count_error_upper_bound"); err != nil {
+		return nil, fmt.Errorf("Aggregation %q: %s", name, err)
+	}
+	if result.SumOtherDocCount, err = intField(agg, "sum_other_doc_count"); err != nil {
+		return nil, fmt.Errorf("Aggregation %q: %s", name, err)
+	}
+
+	switch buckets := agg["buckets"].(type) {
+	case []interface{}:
+		for _, b := range buckets {
+			bucket, err := newAggregationBucket(nil, b)
+			if err != nil {
+				return nil, fmt.Errorf("Aggregation %q: %s", name, err)
+			}
+			result.Buckets = append(result.Buckets, bucket)
+		}
+	case map[string]interface{}:
+		// keyed buckets
+		keys := make([]string, 0, len(buckets))
+		for key := range buckets {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			bucket, err := newAggregationBucket(key, buckets[key])
+			if err != nil {
+				return nil, fmt.Errorf("Aggregation %q: %s", name, err)
+			}
+			result.Buckets = append(result.Buckets, bucket)
+		}
+	default:
+		return nil, fmt.Errorf("Aggregation %q has no buckets", name)
+	}
+
+	return result, nil
+}
+
+// newAggregationBucket decodes a bucket, whose key is given when the buckets
+// are keyed
+func newAggregationBucket(key interface{}, b interface{}) (AggregationBucket, error) {
+	fields, ok := b.(map[string]interface{})
+	if !ok {
+		return AggregationBucket{}, fmt.Errorf("bucket is a %T", b)
+	}
+
+	bucket := AggregationBucket{Key: key}
+	if k, ok := fields["key"]; ok {
+		bucket.Key = k
+	}
+	if k, ok := fields["key_as_string"].(string); ok {
+		bucket.KeyAsString = k
+	}
+
+	count, err := intField(fields, "doc_count")
+	if err != nil {
+		return AggregationBucket{}, err
+	}
+	bucket.DocCount = uint64(count)
+	bucket.Aggregations = subAggregations(fields)
+
+	return bucket, nil
+}
+
+// subAggregations returns the sub-aggregations found in the fields of a bucket
+func subAggregations(fields map[string]interface{}) Aggregations {
+	aggs := Aggregations{}
+	for name, value := range fields {
+		if bucketFields[name] {
+			continue
+		}
+		if agg, ok := value.(map[string]interface{}); ok {
+			aggs[name] = agg
+		}
+	}
+	return aggs
+}
+
+// Time returns the date of a date_histogram bucket, whose key is a number of
+// milliseconds since the epoch
+func (b AggregationBucket) Time() (time.Time, error) {
+	ms, ok := b.Key.(float64)
+	if !ok {
+		return time.Time{}, fmt.Errorf("Bucket key is a %T, not a date", b.Key)
+	}
+	return time.Unix(0, int64(ms)*int64(time.Millisecond)).UTC(), nil
+}
+
+// Filter returns the result of the filter aggregation of the given name
+func (a Aggregations) Filter(name string) (*SingleBucket, error) {
+	return a.SingleBucket(name)
+}
+
+// Nested returns the result of the nested aggregation of the given name
+func (a Aggregations) Nested(name string) (*SingleBucket, error) {
+	return a.SingleBucket(name)
+}
+
+// SingleBucket returns the result of the single bucket aggregation of the given name
+func (a Aggregations) SingleBucket(name string) (*SingleBucket, error) {
+	agg, err := a.get(name)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, ok := agg["doc_count"]; !ok {
+		return nil, fmt.Errorf("Aggregation %q has no doc_count", name)
+	}
+	count, err := intField(agg, "doc_count")
+	if err != nil {
+		return nil, fmt.Errorf("Aggregation %q: %s", name, err)
+	}
+
+	return &SingleBucket{DocCount: uint64(count), Aggregations: subAggregations(agg)}, nil
+}
+
+// Avg returns the result of the avg aggregation of the given name
+func (a Aggregations) Avg(name string) (*MetricValue, error) {
+	return a.Value(name)
+}
+
+// Sum returns the result of the sum aggregation of the given name
+func (a Aggregations) Sum(name string) (*MetricValue, error) {
+	return a.Value(name)
+}
+
+// Min returns the result of the min aggregation of the given name
+func (a Aggregations) Min(name string) (*MetricValue, error) {
+	return a.Value(name)
+}
+
+// Max returns the result of the max aggregation of the given name
+func (a Aggregations) Max(name string) (*MetricValue, error) {
+	return a.Value(name)
+}
+
+// Cardinality returns the result of the cardinality aggregation of the given name
+func (a Aggregations) Cardinality(name string) (*MetricValue, error) {
+	return a.Value(name)
+}
+
+// ValueCount returns the result of the value_count aggregation of the given name
+func (a Aggregations) ValueCount(name string) (*MetricValue, error) {
+	return a.Value(name)
+}
+
+// Value returns the result of the single value metric or pipeline aggregation
+// of the given name
+func (a Aggregations) Value(name string) (*MetricValue, error) {
+	agg, err := a.get(name)
+	if err != nil {
+		return nil, err
+	}
+
+	value, ok := agg["value"]
+	if !ok {
+		return nil, fmt.Errorf("Aggregation %q has no value", name)
+	}
+	result := &MetricValue{}
+	if result.Value, err = number(value); err != nil {
+		return nil, fmt.Errorf("Aggregation %q: %s", name, err)
+	}
+	result.ValueAsString, _ = agg["value_as_string"].(string)
+
+	return result, nil
+}
+
+// Stats returns the result of the stats aggregation of the given name
+func (a Aggregations) Stats(name string) (*Stats, error) {
+	agg, err := a.get(name)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := agg["count"]; !ok {
+		return nil, fmt.Errorf("Aggregation %q has no count", name)
+	}
+
+	result := &Stats{}
+	count, err := intField(agg, "count")
+	if err != nil {
+		return nil, fmt.Errorf("Aggregation %q: %s", name, err)
+	}
+	result.Count = uint64(count)
+
+	for field, value := range map[string]**float64{"min": &result.Min, "max": &result.Max, "avg": &result.Avg} {
+		if *value, err = number(agg[field]); err != nil {
+			return nil, fmt.Errorf("Aggregation %q: %s", name, err)
+		}
+	}
+	sum, err := number(agg["sum"])
+	if err != nil {
+		return nil, fmt.Errorf("Aggregation %q: %s", name, err)
+	}
+	if sum != nil {
+		result.Sum = *sum
+	}
+
+	return result, nil
+}
+
+// Percentiles returns the result of the percentiles aggregation of the given name
+func (a Aggregations) Percentiles(name string) (Percentiles, error) {
+	agg, err := a.get(name)
+	if err != nil {
+		return nil, err
+	}
+
+	result := Percentiles{}
+	switch values := agg["values"].(type) {
+	case map[string]interface{}:
+		// keyed by percent, e.g. {"50.0": 12.5}
+		for key, v := range values {
+			if _, isString := v.(string); isString {
+				// the *_as_string values
+				continue
+			}
+			percent, err := strconv.ParseFloat(key, 64)
+			if err != nil {
+				return nil, fmt.Errorf("Aggregation %q: invalid percent %q", name, key)
+			}
+			value, err := number(v)
+			if err != nil {
+				return nil, fmt.Errorf("Aggregation %q: %s", name, err)
+			}
+			result = append(result, Percentile{Percent: percent, Value: value})
+		}
+	case []interface{}:
+		// not keyed, e.g. [{"key": 50.0, "value": 12.5}]
+		for _, v := range values {
+			fields, ok := v.(map[string]interface{})
+			if !ok {
+				return nil, fmt.Errorf("Aggregation %q: percentile is a %T", name, v)
+			}
+			percent, ok := fields["key"].(float64)
+			if !ok {
+				return nil, fmt.Errorf("Aggregation %q: percentile has no key", name)
+			}
+			value, err := number(fields["value"])
+			if err != nil {
+				return nil, fmt.Errorf("Aggregation %q: %s", name, err)
+			}
+			result = append(result, Percentile{Percent: percent, Value: value})
+		}
+	default:
+		return nil, fmt.Errorf("Aggregation %q has no percentiles", name)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Percent < result[j].Percent
+	})
+	return result, nil
+}
+
+// Value returns the value of the given percentile. ok is false when there is
+// no such percentile or it has no value.
+func (p Percentiles) Value(percent float64) (value float64, ok bool) {
+	for _, percentile := range p {
+		if percentile.Percent == percent && percentile.Value != nil {
+			return *percentile.Value, true
+		}
+	}
+	return 0, false
+}
+
+// TopHits returns the result of the top_hits aggregation of the given name
+func (a Aggregations) TopHits(name string) (*TopHits, error) {
+	agg, err := a.get(name)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := agg["hits"].(map[string]interface{}); !ok {
+		return nil, fmt.Errorf("Aggregation %q has no hits", name)
+	}
+
+	// The hits are decoded the same way as the ones of a search
+	data, err := json.Marshal(agg["hits"])
+	if err != nil {
+		return nil, err
+	}
+	var hits Hits
+	if err := json.Unmarshal(data, &hits); err != nil {
+		return nil, fmt.Errorf("Aggregation %q: %s", name, err)
+	}
+
+	result := &TopHits{Total: hits.Total, Hits: hits.Hits}
+	if result.MaxScore, err = number(hits.MaxScore); err != nil {
+		return nil, fmt.Errorf("Aggregation %q: %s", name, err)
+	}
+	return result, nil
+}
+
+// number returns the value of a numeric field, nil when it is null or missing
+func number(v interface{}) (*float64, error) {
+	switch n := v.(type) {
+	case nil:
+		return nil, nil
+	case float64:
+		return &n, nil
+	}
+	return nil, fmt.Errorf("%v is not a number", v)
+}
+
+// intField returns the value of an integer field, 0 when it is missing
+func intField(fields map[string]interface{}, name string) (int64, error) {
+	v, ok := fields[name]
+	if !ok {
+		return 0, nil
+	}
+	n, ok := v.(float64)
+	if !ok {
+		return 0, fmt.Errorf("%s is a %T, not a number", name, v)
+	}
+	return int64(n), nil
+}
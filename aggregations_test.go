@@ -0,0 +1,188 @@
+package goes
+
+import (
+	"encoding/json"
+	"time"
+
+	. "github.com/go-check/check"
+)
+
+// aggregationsResponse is the aggregations part of a search response
+const aggregationsResponse = `{
+	"users": {
+		"doc_count_error_upper_bound": 0,
+		"sum_other_doc_count": 12,
+		"buckets": [
+			{"key": "foo", "doc_count": 3, "age": {"value": 25.5}},
+			{"key": "bar", "doc_count": 1, "age": {"value": null}}
+		]
+	},
+	"per_day": {
+		"buckets": [
+			{"key_as_string": "2021-01-01", "key": 1609459200000, "doc_count": 2},
+			{"key_as_string": "2021-01-02", "key": 1609545600000, "doc_count": 0}
+		]
+	},
+	"ranges": {
+		"buckets": {
+			"old": {"from": 50, "doc_count": 1},
+			"young": {"to": 50, "doc_count": 3, "likes": {"value": 7, "value_as_string": "7.0"}}
+		}
+	},
+	"stats": {"count": 0, "min": null, "max": null, "avg": null, "sum": 0},
+	"age_stats": {"count": 4, "min": 18, "max": 40, "avg": 29.5, "sum": 118},
+	"load_time": {"values": {"99.0": 120.5, "50.0": 12.5, "50.0_as_string": "12.5"}},
+	"load_time_array": {"values": [{"key": 50.0, "value": 12.5}, {"key": 99.0, "value": null}]},
+	"latest": {
+		"hits": {
+			"total": {"value": 3, "relation": "eq"},
+			"max_score": null,
+			"hits": [{"_index": "i", "_type": "_doc", "_id": "1", "_score": null, "_source": {"user": "foo"}, "sort": [1609459200000]}]
+		}
+	},
+	"latest_1x": {"hits": {"total": 3, "max_score": 1.0, "hits": []}},
+	"foo": {"doc_count": 3, "age": {"value": 25.5}},
+	"comments": {"doc_count": 5, "authors": {"buckets": [{"key": "bar", "doc_count": 5}]}},
+	"broken": {"buckets": "none", "value": "none", "count": "none", "values": 1, "hits": {"hits": 1}, "doc_count": "none"}
+}`
+
+func aggregations(c *C) Aggregations {
+	var aggs Aggregations
+	c.Assert(json.Unmarshal([]byte(aggregationsResponse), &aggs), IsNil)
+	return aggs
+}
+
+func (s *GoesTestSuite) TestAggregationsBuckets(c *C) {
+	aggs := aggregations(c)
+
+	users, err := aggs.Terms("users")
+	c.Assert(err, IsNil)
+	c.Assert(users.SumOtherDocCount, Equals, int64(12))
+	c.Assert(users.Buckets, HasLen, 2)
+	c.Assert(users.Buckets[0].Key, Equals, "foo")
+	c.Assert(users.Buckets[0].DocCount, Equals, uint64(3))
+	age, err := users.Buckets[0].Aggregations.Avg("age")
+	c.Assert(err, IsNil)
+	c.Assert(*age.Value, Equals, 25.5)
+	age, err = users.Buckets[1].Aggregations.Avg("age")
+	c.Assert(err, IsNil)
+	c.Assert(age.Value, IsNil)
+
+	perDay, err := aggs.DateHistogram("per_day")
+	c.Assert(err, IsNil)
+	c.Assert(perDay.Buckets, HasLen, 2)
+	c.Assert(perDay.Buckets[1].KeyAsString, Equals, "2021-01-02")
+	c.Assert(perDay.Buckets[1].DocCount, Equals, uint64(0))
+	date, err := perDay.Buckets[1].Time()
+	c.Assert(err, IsNil)
+	c.Assert(date, Equals, time.Date(2021, 1, 2, 0, 0, 0, 0, time.UTC))
+	_, err = users.Buckets[0].Time()
+	c.Assert(err, ErrorMatches, "Bucket key is a string, not a date")
+
+	ranges, err := aggs.Buckets("ranges")
+	c.Assert(err, IsNil)
+	c.Assert(ranges.Buckets, HasLen, 2)
+	c.Assert(ranges.Buckets[0].Key, Equals, "old")
+	c.Assert(ranges.Buckets[0].Aggregations, HasLen, 0)
+	c.Assert(ranges.Buckets[1].Key, Equals, "young")
+	likes, err := ranges.Buckets[1].Aggregations.Value("likes")
+	c.Assert(err, IsNil)
+	c.Assert(*likes.Value, Equals, 7.0)
+	c.Assert(likes.ValueAsString, Equals, "7.0")
+
+	foo, err := aggs.Filter("foo")
+	c.Assert(err, IsNil)
+	c.Assert(foo.DocCount, Equals, uint64(3))
+	age, err = foo.Aggregations.Avg("age")
+	c.Assert(err, IsNil)
+	c.Assert(*age.Value, Equals, 25.5)
+
+	comments, err := aggs.Nested("comments")
+	c.Assert(err, IsNil)
+	c.Assert(comments.DocCount, Equals, uint64(5))
+	authors, err := comments.Aggregations.Terms("authors")
+	c.Assert(err, IsNil)
+	c.Assert(authors.Buckets, HasLen, 1)
+	c.Assert(authors.Buckets[0].Key, Equals, "bar")
+}
+
+func (s *GoesTestSuite) TestAggregationsMetrics(c *C) {
+	aggs := aggregations(c)
+
+	stats, err := aggs.Stats("stats")
+	c.Assert(err, IsNil)
+	c.Assert(stats, DeepEquals, &Stats{})
+
+	stats, err = aggs.Stats("age_stats")
+	c.Assert(err, IsNil)
+	c.Assert(stats.Count, Equals, uint64(4))
+	c.Assert(*stats.Min, Equals, 18.0)
+	c.Assert(*stats.Max, Equals, 40.0)
+	c.Assert(*stats.Avg, Equals, 29.5)
+	c.Assert(stats.Sum, Equals, 118.0)
+
+	for _, name := range []string{"load_time", "load_time_array"} {
+		percentiles, err := aggs.Percentiles(name)
+		c.Assert(err, IsNil)
+		c.Assert(percentiles, HasLen, 2)
+		c.Assert(percentiles[0].Percent, Equals, 50.0)
+		c.Assert(percentiles[1].Percent, Equals, 99.0)
+		value, ok := percentiles.Value(50)
+		c.Assert(ok, Equals, true)
+		c.Assert(value, Equals, 12.5)
+		_, ok = percentiles.Value(75)
+		c.Assert(ok, Equals, false)
+	}
+
+	latest, err := aggs.TopHits("latest")
+	c.Assert(err, IsNil)
+	c.Assert(latest.Total, Equals, uint64(3))
+	c.Assert(latest.MaxScore, IsNil)
+	c.Assert(latest.Hits, HasLen, 1)
+	c.Assert(latest.Hits[0].ID, Equals, "1")
+	c.Assert(latest.Hits[0].Source, DeepEquals, map[string]interface{}{"user": "foo"})
+	c.Assert(latest.Hits[0].Sort, DeepEquals, []json.RawMessage{json.RawMessage("1609459200000")})
+
+	latest, err = aggs.TopHits("latest_1x")
+	c.Assert(err, IsNil)
+	c.Assert(latest.Total, Equals, uint64(3))
+	c.Assert(*latest.MaxScore, Equals, 1.0)
+	c.Assert(latest.Hits, HasLen, 0)
+}
+
+func (s *GoesTestSuite) TestAggregationsErrors(c *C) {
+	aggs := aggregations(c)
+
+	_, err := aggs.Terms("missing")
+	c.Assert(err, ErrorMatches, `Aggregation "missing" not found`)
+	_, err = aggs.Avg("missing")
+	c.Assert(err, ErrorMatches, `Aggregation "missing" not found`)
+
+	_, err = aggs.Terms("broken")
+	c.Assert(err, ErrorMatches, `Aggregation "broken" has no buckets`)
+	_, err = aggs.Terms("stats")
+	c.Assert(err, ErrorMatches, `Aggregation "stats" has no buckets`)
+	_, err = aggs.Avg("broken")
+	c.Assert(err, ErrorMatches, `Aggregation "broken": none is not a number`)
+	_, err = aggs.Avg("users")
+	c.Assert(err, ErrorMatches, `Aggregation "users" has no value`)
+	_, err = aggs.Stats("broken")
+	c.Assert(err, ErrorMatches, `Aggregation "broken": count is a string, not a number`)
+	_, err = aggs.Stats("foo")
+	c.Assert(err, ErrorMatches, `Aggregation "foo" has no count`)
+	_, err = aggs.Percentiles("broken")
+	c.Assert(err, ErrorMatches, `Aggregation "broken" has no percentiles`)
+	_, err = aggs.TopHits("broken")
+	c.Assert(err, ErrorMatches, `Aggregation "broken": .*`)
+	_, err = aggs.TopHits("users")
+	c.Assert(err, ErrorMatches, `Aggregation "users" has no hits`)
+	_, err = aggs.Filter("broken")
+	c.Assert(err, ErrorMatches, `Aggregation "broken": doc_count is a string, not a number`)
+	_, err = aggs.Nested("users")
+	c.Assert(err, ErrorMatches, `Aggregation "users" has no doc_count`)
+
+	var bad Aggregations
+	c.Assert(json.Unmarshal([]byte(`{"users": {"buckets": [1]}}`), &bad), IsNil)
+	_, err = bad.Terms("users")
+	c.Assert(err, ErrorMatches, `Aggregation "users": bucket is a float64`)
+}
@@ -0,0 +1,162 @@
+package goes
+
+import (
+	"bytes"
+	"encoding/base64"
+	"io"
+	"io/ioutil"
+	"net/http"
+)
+
+// Authenticator adds credentials to the requests sent by a Client
+//
+// Authenticate is called once the URL of the request points to the node it is
+// sent to, right before it is sent. body holds the bytes of the request body
+// for signers which need to hash it, it must not be modified. Streamed bodies,
+// such as the ones of BulkRequest, are read ahead for this, unless the
+// Authenticator is a BodylessAuthenticator.
+type Authenticator interface {
+	Authenticate(req *http.Request, body []byte) error
+}
+
+// BodylessAuthenticator is implemented by the Authenticators which do not use
+// the request body, such as the ones only setting a header. Streamed bodies
+// are then sent as they are generated, body being nil for them.
+type BodylessAuthenticator interface {
+	Authenticator
+	IgnoresBody() bool
+}
+
+// AuthenticatorFunc allows the use of an ordinary function as an Authenticator
+type AuthenticatorFunc func(req *http.Request, body []byte) error
+
+// Authenticate calls f(req, body)
+func (f AuthenticatorFunc) Authenticate(req *http.Request, body []byte) error {
+	return f(req, body)
+}
+
+// BasicAuth authenticates requests using HTTP basic authentication
+type BasicAuth struct {
+	Username string
+	Password string
+}
+
+// Authenticate sets the basic authentication header of req
+func (a BasicAuth) Authenticate(req *http.Request, body []byte) error {
+	req.SetBasicAuth(a.Username, a.Password)
+	return nil
+}
+
+// IgnoresBody returns true, the body of the requests not being authenticated
+func (a BasicAuth) IgnoresBody() bool {
+	return true
+}
+
+// APIKeyAuth authenticates requests using an elasticsearch API key
+type APIKeyAuth struct {
+	// ID of the API key. When empty, Key is expected to be already encoded as
+	// returned in the "encoded" field by the create API key API.
+	ID  string
+	Key string
+}
+
+// Authenticate sets the "ApiKey" authorization header of req
+func (a APIKeyAuth) Authenticate(req *http.Request, body []byte) error {
+	key := a.Key
+	if a.ID != "" {
+		key = base64.StdEncoding.EncodeToString([]byte(a.ID + ":" + a.Key))
+	}
+	req.Header.Set("Authorization", "ApiKey "+key)
+	return nil
+}
+
+// IgnoresBody returns true, the body of the requests not being authenticated
+func (a APIKeyAuth) IgnoresBody() bool {
+	return true
+}
+
+// BearerAuth authenticates requests using a bearer token
+type BearerAuth struct {
+	Token string
+}
+
+// Authenticate sets the "Bearer" authorization header of req
+func (a BearerAuth) Authenticate(req *http.Request, body []byte) error {
+	req.Header.Set("Authorization", "Bearer "+a.Token)
+	return nil
+}
+
+// IgnoresBody returns true, the body of the requests not being authenticated
+func (a BearerAuth) IgnoresBody() bool {
+	return true
+}
+
+// WithAuthenticator sets the Authenticator adding credentials to every request.
+// Returns the original client.
+func (c *Client) WithAuthenticator(a Authenticator) *Client {
+	c.auth = a
+	return c
+}
+
+// authenticate runs the authenticator of the client, if any, on req
+func (c *Client) authenticate(req *http.Request) error {
+	if c.auth == nil {
+		return nil
+	}
+
+	if req.ContentLength < 0 {
+		if a, ok := c.auth.(BodylessAuthenticator); ok && a.IgnoresBody() {
+			return c.auth.Authenticate(req, nil)
+		}
+		if err := bufferBody(req); err != nil {
+			return err
+		}
+	}
+
+	body, err := requestBody(req)
+	if err != nil {
+		return err
+	}
+	return c.auth.Authenticate(req, body)
+}
+
+// bufferBody reads the streamed body of req, which is then sent from memory
+// with a known length
+func bufferBody(req *http.Request) error {
+	b, err := ioutil.ReadAll(req.Body)
+	req.Body.Close()
+	if err != nil {
+		return err
+	}
+
+	req.Body = ioutil.NopCloser(bytes.NewReader(b))
+	req.ContentLength = int64(len(b))
+	req.GetBody = func() (io.ReadCloser, error) {
+		return ioutil.NopCloser(bytes.NewReader(b)), nil
+	}
+	return nil
+}
+
+// requestBody returns the bytes of the body of req, leaving the body readable
+func requestBody(req *http.Request) ([]byte, error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return nil, nil
+	}
+
+	if req.GetBody != nil {
+		body, err := req.GetBody()
+		if err != nil {
+			return nil, err
+		}
+		defer body.Close()
+		return ioutil.ReadAll(body)
+	}
+
+	b, err := ioutil.ReadAll(req.Body)
+	req.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	req.Body = ioutil.NopCloser(bytes.NewReader(b))
+	return b, nil
+}
@@ -0,0 +1,132 @@
+package goes
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+
+	. "github.com/go-check/check"
+)
+
+// newHeaderServer starts a test server checking the Authorization header of
+// every request
+func newHeaderServer(c *C, expected string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		c.Check(r.Header.Get("Authorization"), Equals, expected)
+		w.Write([]byte("{}"))
+	}))
+}
+
+func (s *GoesTestSuite) TestAuthenticators(c *C) {
+	authenticators := []struct {
+		auth     Authenticator
+		expected string
+	}{
+		{BasicAuth{"elastic", "changeme"}, "Basic ZWxhc3RpYzpjaGFuZ2VtZQ=="},
+		{APIKeyAuth{ID: "VuaCfGcBCdbkQm-e5aOx", Key: "ui2lp2axTNmsyakw9tvNnw"}, "ApiKey VnVhQ2ZHY0JDZGJrUW0tZTVhT3g6dWkybHAyYXhUTm1zeWFrdzl0dk5udw=="},
+		{APIKeyAuth{Key: "VnVhQ2ZHY0JDZGJrUW0tZTVhT3g6dWkybHAyYXhUTm1zeWFrdzl0dk5udw=="}, "ApiKey VnVhQ2ZHY0JDZGJrUW0tZTVhT3g6dWkybHAyYXhUTm1zeWFrdzl0dk5udw=="},
+		{BearerAuth{"dGhpcyBpcyBub3QgYSByZWFsIHRva2VuIQ=="}, "Bearer dGhpcyBpcyBub3QgYSByZWFsIHRva2VuIQ=="},
+	}
+
+	for _, a := range authenticators {
+		ts := newHeaderServer(c, a.expected)
+		conn, err := NewClientFromURL(ts.URL)
+		c.Assert(err, IsNil)
+
+		_, err = conn.WithAuthenticator(a.auth).Do(&Request{Method: "GET"})
+		c.Assert(err, IsNil)
+		ts.Close()
+	}
+}
+
+func (s *GoesTestSuite) TestAuthenticatorOverridesUserInfo(c *C) {
+	ts := newHeaderServer(c, "Bearer token")
+	defer ts.Close()
+
+	conn, err := NewClientFromURL(strings.Replace(ts.URL, "http://", "http://user:pass@", 1))
+	c.Assert(err, IsNil)
+
+	_, err = conn.WithAuthenticator(BearerAuth{"token"}).Do(&Request{Method: "GET"})
+	c.Assert(err, IsNil)
+}
+
+func (s *GoesTestSuite) TestAuthenticatorSigner(c *C) {
+	secret := []byte("secret")
+	sign := func(method, path string, body []byte) string {
+		mac := hmac.New(sha256.New, secret)
+		mac.Write([]byte(method + "\n" + path + "\n"))
+		mac.Write(body)
+		return hex.EncodeToString(mac.Sum(nil))
+	}
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		c.Check(string(body), Equals, `{"query":"foo"}`)
+		c.Check(r.Header.Get("X-Signature"), Equals, sign(r.Method, r.URL.Path, body))
+		w.Write([]byte("{}"))
+	}))
+	defer ts.Close()
+
+	conn, err := NewClientFromURL(ts.URL + "/prefix")
+	c.Assert(err, IsNil)
+	conn.WithAuthenticator(AuthenticatorFunc(func(req *http.Request, body []byte) error {
+		c.Check(req.URL.Host, Equals, strings.TrimPrefix(ts.URL, "http://"))
+		req.Header.Set("X-Signature", sign(req.Method, req.URL.Path, body))
+		return nil
+	}))
+
+	_, err = conn.Search(map[string]interface{}{"query": "foo"}, []string{"i"}, nil, nil)
+	c.Assert(err, IsNil)
+}
+
+func (s *GoesTestSuite) TestAuthenticatorStreamedBody(c *C) {
+	var signed [][]byte
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		c.Check(r.ContentLength, Equals, int64(len(body)))
+		c.Check(signed[len(signed)-1], DeepEquals, body)
+		if strings.HasSuffix(r.URL.Path, "/_msearch") {
+			w.Write([]byte(`{"responses":[{}]}`))
+		} else {
+			w.Write([]byte(`{"items":[{"index":{"status":201}}]}`))
+		}
+	}))
+	defer ts.Close()
+
+	conn, err := NewClientFromURL(ts.URL)
+	c.Assert(err, IsNil)
+	conn.WithAuthenticator(AuthenticatorFunc(func(req *http.Request, body []byte) error {
+		signed = append(signed, body)
+		return nil
+	}))
+
+	_, err = conn.BulkSend([]Document{{Index: "i", ID: "1", BulkCommand: BulkCommandIndex, Fields: map[string]interface{}{"n": 1}}})
+	c.Assert(err, IsNil)
+	_, err = conn.MultiSearch([]MultiSearchEntry{{IndexList: []string{"i"}}}, nil)
+	c.Assert(err, IsNil)
+
+	c.Assert(signed, HasLen, 2)
+	c.Assert(string(signed[0]), Equals, `{"index":{"_id":"1","_index":"i"}}`+"\n"+`{"n":1}`+"\n")
+	c.Assert(string(signed[1]), Equals, `{"index":["i"]}`+"\n{}\n")
+}
+
+func (s *GoesTestSuite) TestBodylessAuthenticatorStreams(c *C) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Streamed bodies are sent with an unknown length
+		c.Check(r.ContentLength, Equals, int64(-1))
+		c.Check(r.Header.Get("Authorization"), Equals, "Bearer token")
+		w.Write([]byte(`{"items":[{"index":{"status":201}}]}`))
+	}))
+	defer ts.Close()
+
+	conn, err := NewClientFromURL(ts.URL)
+	c.Assert(err, IsNil)
+	conn.WithAuthenticator(BearerAuth{"token"})
+
+	_, err = conn.BulkSend([]Document{{Index: "i", ID: "1", BulkCommand: BulkCommandIndex, Fields: map[string]interface{}{"n": 1}}})
+	c.Assert(err, IsNil)
+}
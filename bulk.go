@@ -0,0 +1,379 @@
+package goes
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sync"
+)
+
+// legacyError matches the errors returned as strings by ES 1.x and 2.x, such
+// as "DocumentAlreadyExistsException[[i][2] [t][1]: document already exists]"
+var legacyError = regexp.MustCompile(`^(\w+)\[(.*)\]$`)
+
+// UnmarshalJSON decodes an item of a _bulk response. The error of the item is
+// a string up to ES 2.x and an object afterwards; in both cases Cause is set.
+func (i *Item) UnmarshalJSON(data []byte) error {
+	type item Item
+	var raw struct {
+		item
+		RawError json.RawMessage `json:"error"`
+	}
+
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	*i = Item(raw.item)
+
+	var err error
+	i.Error, i.Cause, err = decodeError(raw.RawError)
+	return err
+}
+
+// decodeError decodes the error field of a response or of an item, a string
+// up to ES 2.x and an object afterwards. The error is returned both as a
+// string, the JSON object itself for the latter, and structured.
+func decodeError(raw json.RawMessage) (string, *ErrorCause, error) {
+	if isNull(raw) {
+		return "", nil, nil
+	}
+
+	if raw[0] != '"' {
+		cause := &ErrorCause{}
+		if err := json.Unmarshal(raw, cause); err != nil {
+			return string(raw), nil, err
+		}
+		return string(raw), cause, nil
+	}
+
+	var msg string
+	if err := json.Unmarshal(raw, &msg); err != nil {
+		return "", nil, err
+	}
+	if m := legacyError.FindStringSubmatch(msg); m != nil {
+		return msg, &ErrorCause{Type: m[1], Reason: m[2]}, nil
+	}
+	return msg, &ErrorCause{Reason: msg}, nil
+}
+
+// Error returns a summary of the failures, detailing the first one
+func (err *BulkError) Error() string {
+	total := len(err.Failed) + len(err.Succeeded)
+	if len(err.Failed) == 0 {
+		return fmt.Sprintf("0 of %d bulk items failed", total)
+	}
+
+	first := err.Failed[0]
+	return fmt.Sprintf("%d of %d bulk items failed, first failure at position %d: [%d] %s: %s",
+		len(err.Failed), total, first.Position, first.Status, first.ErrorType, first.ErrorReason)
+}
+
+// newBulkError lists the results of every item of a _bulk response for the
+// given documents. It returns nil when no item failed.
+func newBulkError(resp *Response, documents []Document) *BulkError {
+	err := &BulkError{}
+
+	for position, item := range resp.Items {
+		for action, i := range item {
+			result := BulkItemResult{
+				Position: position,
+				Action:   action,
+				Index:    i.Index,
+				Type:     i.Type,
+				ID:       i.ID,
+				Status:   i.Status,
+			}
+			if position < len(documents) {
+				result.Document = documents[position]
+			}
+
+			if i.Error == "" {
+				err.Succeeded = append(err.Succeeded, result)
+				continue
+			}
+
+			result.ErrorReason = i.Error
+			if i.Cause != nil {
+				result.ErrorType = i.Cause.Type
+				result.ErrorReason = i.Cause.Reason
+			}
+			err.Failed = append(err.Failed, result)
+		}
+	}
+
+	if len(err.Failed) == 0 {
+		return nil
+	}
+	return err
+}
+
+// BulkSendRetry is the same as BulkSend, but the documents rejected with a
+// status code retryable under policy, such as 429, are sent again on their own
+// until they succeed or policy.MaxAttempts is reached. DefaultRetryPolicy is
+// used when policy is nil.
+//
+// The items of the returned response are in the order of documents, each one
+// being the outcome of the last attempt for its document. When some documents
+// still failed, a *BulkError is returned. When a retry fails with any other
+// error, the response still holds the outcomes of the previous attempts, the
+// documents left to retry keeping their failure.
+func (c *Client) BulkSendRetry(documents []Document, policy *RetryPolicy) (*Response, error) {
+	return c.BulkSendRetryContext(context.Background(), documents, policy)
+}
+
+// BulkSendRetryContext is the same as BulkSendRetry, but the requests are bound to ctx
+func (c *Client) BulkSendRetryContext(ctx context.Context, documents []Document, policy *RetryPolicy) (*Response, error) {
+	if policy == nil {
+		policy = &DefaultRetryPolicy
+	}
+
+	resp := &Response{}
+	items := make([]map[string]Item, len(documents))
+
+	version := c.bulkVersion(ctx, documents...)
+
+	// positions in documents of the documents to send
+	pending := make([]int, len(documents))
+	for i := range pending {
+		pending[i] = i
+	}
+
+	// partial returns the response of the first attempt when a later one
+	// fails, its items being the outcomes of the attempts made so far. The
+	// pending documents keep the failure of their previous attempt.
+	partial := func() *Response {
+		resp.Items = items
+		resp.Errors = true
+		return resp
+	}
+
+	for attempt := 1; len(pending) > 0; attempt++ {
+		if attempt > 1 {
+			if err := sleepContext(ctx, policy.delay(attempt-1)); err != nil {
+				return partial(), err
+			}
+		}
+
+		batch := make([]Document, len(pending))
+		for j, position := range pending {
+			batch[j] = documents[position]
+		}
+
+		r, err := c.sendBulk(ctx, batch, &BulkRequest{Documents: batch, Version: version})
+		if _, ok := err.(*BulkError); err != nil && !ok {
+			if attempt > 1 {
+				return partial(), err
+			}
+			return r, err
+		}
+		if len(r.Items) != len(batch) {
+			err = fmt.Errorf("Expected %d items in bulk response, got %d", len(batch), len(r.Items))
+			if attempt > 1 {
+				return partial(), err
+			}
+			return r, err
+		}
+		if attempt == 1 {
+			resp = r
+		}
+
+		retry := []int{}
+		for j, item := range r.Items {
+			items[pending[j]] = item
+			if attempt < policy.MaxAttempts && retryableItem(policy, item) {
+				retry = append(retry, pending[j])
+			}
+		}
+		pending = retry
+	}
+
+	resp.Items = items
+	bulkErr := newBulkError(resp, documents)
+	resp.Errors = bulkErr != nil
+	if bulkErr != nil {
+		return resp, bulkErr
+	}
+	return resp, nil
+}
+
+// retryableItem reports whether the document of a bulk response item failed
+// with a status code worth a retry
+func retryableItem(policy *RetryPolicy, item map[string]Item) bool {
+	for _, i := range item {
+		if i.Error != "" && policy.retryableStatus(i.Status) {
+			return true
+		}
+	}
+	return false
+}
+
+// BulkSplitConfig holds the limits used by BulkSendSplit to split documents
+// into several _bulk requests
+type BulkSplitConfig struct {
+	// Maximum number of documents in a request, no limit when zero
+	MaxDocuments int
+
+	// Maximum size in bytes of the body of a request, no limit when zero. A
+	// document larger than this is sent in a request of its own.
+	MaxBytes int
+
+	// Number of requests sent concurrently, 1 when zero
+	Concurrency int
+}
+
+// bulkBatch is a part of the documents given to BulkSendSplit and the
+// response to its request
+type bulkBatch struct {
+	documents []Document
+	bulkData  []byte
+	response  *Response
+}
+
+// BulkSendSplit is the same as BulkSend, but the documents are split into as
+// many requests as needed to stay within the limits of config, such as the
+// http.max_content_length setting of the cluster.
+//
+// The items of the responses are merged into the returned response in the
+// order of documents. When some documents failed, a *BulkError is returned.
+// Any other error stops the sending of the remaining requests, and is
+// returned along with the items of the requests which succeeded, the other
+// documents being reported as failed with that error.
+func (c *Client) BulkSendSplit(documents []Document, config BulkSplitConfig) (*Response, error) {
+	return c.BulkSendSplitContext(context.Background(), documents, config)
+}
+
+// BulkSendSplitContext is the same as BulkSendSplit, but the requests are bound to ctx
+func (c *Client) BulkSendSplitContext(ctx context.Context, documents []Document, config BulkSplitConfig) (*Response, error) {
+	if config.Concurrency <= 0 {
+		config.Concurrency = 1
+	}
+
+	version := c.bulkVersion(ctx, documents...)
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		batches []*bulkBatch
+		wg      sync.WaitGroup
+		sem     = make(chan struct{}, config.Concurrency)
+
+		// failure is the first error which is not a *BulkError
+		lock    sync.Mutex
+		failure error
+	)
+	fail := func(err error) {
+		lock.Lock()
+		if failure == nil {
+			failure = err
+		}
+		lock.Unlock()
+		cancel()
+	}
+	send := func(batch *bulkBatch) {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			fail(ctx.Err())
+			return
+		}
+		batches = append(batches, batch)
+
+		wg.Add(1)
+		go func() {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+
+			r := Request{
+				Method:   "POST",
+				API:      "_bulk",
+				BulkData: batch.bulkData,
+			}
+			response, err := c.sendBulk(ctx, batch.documents, &r)
+			if _, ok := err.(*BulkError); err != nil && !ok {
+				fail(err)
+				return
+			}
+			if len(response.Items) != len(batch.documents) {
+				fail(fmt.Errorf("Expected %d items in bulk response, got %d", len(batch.documents), len(response.Items)))
+				return
+			}
+			batch.response = response
+		}()
+	}
+
+	batch := &bulkBatch{}
+	start := 0
+	var lines bytes.Buffer
+	for i, doc := range documents {
+		if err := ctx.Err(); err != nil {
+			fail(err)
+			break
+		}
+
+		lines.Reset()
+		if err := encodeBulkDocument(&lines, doc, version); err != nil {
+			fail(err)
+			break
+		}
+
+		if i > start &&
+			((config.MaxDocuments > 0 && i-start >= config.MaxDocuments) ||
+				(config.MaxBytes > 0 && len(batch.bulkData)+lines.Len() > config.MaxBytes)) {
+			batch.documents = documents[start:i]
+			send(batch)
+			batch, start = &bulkBatch{}, i
+		}
+		batch.bulkData = append(batch.bulkData, lines.Bytes()...)
+	}
+	if ctx.Err() == nil && start < len(documents) {
+		batch.documents = documents[start:]
+		send(batch)
+	}
+	wg.Wait()
+
+	resp := &Response{Items: make([]map[string]Item, 0, len(documents))}
+	for _, batch := range batches {
+		if batch.response == nil {
+			// The batch was not sent or failed, its documents are
+			// reported as failed with the error which stopped the sending
+			for _, doc := range batch.documents {
+				resp.Items = append(resp.Items, failedItem(doc, failure))
+			}
+			continue
+		}
+		if resp.Status == 0 {
+			resp.Status = batch.response.Status
+		}
+		resp.Took += batch.response.Took
+		resp.Errors = resp.Errors || batch.response.Errors
+		resp.Items = append(resp.Items, batch.response.Items...)
+	}
+
+	if failure != nil {
+		for _, doc := range documents[len(resp.Items):] {
+			resp.Items = append(resp.Items, failedItem(doc, failure))
+		}
+		resp.Errors = true
+		return resp, failure
+	}
+
+	return bulkResult(resp, documents)
+}
+
+// failedItem is the item reporting a document of BulkSendSplit which was
+// not indexed because of err
+func failedItem(doc Document, err error) map[string]Item {
+	item := Item{Type: doc.Type, Error: err.Error()}
+	if doc.Index != nil {
+		item.Index = fmt.Sprint(doc.Index)
+	}
+	if doc.ID != nil {
+		item.ID = fmt.Sprint(doc.ID)
+	}
+	return map[string]Item{doc.BulkCommand: item}
+}
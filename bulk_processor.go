@@ -0,0 +1,241 @@
+package goes
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// BulkProcessorConfig holds the settings of a BulkProcessor
+type BulkProcessorConfig struct {
+	// Number of documents buffered by a worker which triggers a flush, no limit when zero
+	MaxDocuments int
+
+	// Size in bytes of the bulk data buffered by a worker which triggers a flush, no limit when zero
+	MaxBytes int
+
+	// Interval at which the workers flush their documents, no periodic flush when zero
+	FlushInterval time.Duration
+
+	// Number of workers sending batches concurrently, 1 when zero
+	Workers int
+
+	// After is called by the worker once a batch was sent, with the response
+	// and the error returned by BulkSend. It must be safe for concurrent use
+	// when there are several workers.
+	//
+	// After runs on the goroutine of the worker, which handles no document
+	// until it returns. It must not call Add, Flush or Close, which wait for
+	// the workers and would never return: documents to send again, such as
+	// the failed ones, must be added from another goroutine.
+	After func(documents []Document, response *Response, err error)
+}
+
+// BulkProcessor sends documents added one at a time to elasticsearch in batches
+//
+// Documents are spread over the workers of the processor, each worker buffering
+// its documents until a threshold is hit. Documents with the same index and id
+// are always handled by the same worker, so that they are sent in the order
+// they were added.
+type BulkProcessor struct {
+	client  *Client
+	config  BulkProcessorConfig
+	workers []*bulkWorker
+
+	// version of the server, looked up once by the first document depending
+	// on it rather than for every document
+	version     ServerVersion
+	versionOnce sync.Once
+
+	// next counts the documents without id to spread them over the workers
+	next uint32
+
+	// closed is guarded by the lock so that no document is added to a closed worker
+	sync.RWMutex
+	closed bool
+	wg     sync.WaitGroup
+}
+
+// bulkWorker buffers the documents of a BulkProcessor and sends them
+type bulkWorker struct {
+	p     *BulkProcessor
+	items chan bulkItem
+
+	documents []Document
+	bulkData  bytes.Buffer
+}
+
+// bulkItem is either a document to buffer along with its encoded bulk lines,
+// or a request to flush the buffer which is acknowledged by closing flushed
+type bulkItem struct {
+	document Document
+	lines    []byte
+	flushed  chan struct{}
+}
+
+// NewBulkProcessor creates a BulkProcessor sending documents with the client
+// and starts its workers. Close must be called to stop them.
+func (c *Client) NewBulkProcessor(config BulkProcessorConfig) *BulkProcessor {
+	if config.Workers <= 0 {
+		config.Workers = 1
+	}
+
+	p := &BulkProcessor{
+		client:  c,
+		config:  config,
+		workers: make([]*bulkWorker, config.Workers),
+	}
+
+	for i := range p.workers {
+		w := &bulkWorker{p: p, items: make(chan bulkItem, 64)}
+		p.workers[i] = w
+		p.wg.Add(1)
+		go w.run()
+	}
+
+	return p
+}
+
+// Add queues a document to be sent in the next batch of its worker, waiting
+// while the queue of the worker is full. It only returns an error when the
+// document can not be encoded or the processor is closed; errors happening
+// while sending it are reported to After.
+func (p *BulkProcessor) Add(doc Document) error {
+	var version ServerVersion
+	if bulkNeedsVersion(doc) {
+		p.versionOnce.Do(func() {
+			p.version = p.client.layoutVersion(context.Background())
+		})
+		version = p.version
+	}
+
+	var lines bytes.Buffer
+	if err := encodeBulkDocument(&lines, doc, version); err != nil {
+		return err
+	}
+
+	p.RLock()
+	defer p.RUnlock()
+
+	if p.closed {
+		return errors.New("Bulk processor is closed")
+	}
+
+	p.worker(doc).items <- bulkItem{document: doc, lines: lines.Bytes()}
+	return nil
+}
+
+// worker returns the worker handling doc. Documents without id are spread over
+// the workers in round-robin order.
+func (p *BulkProcessor) worker(doc Document) *bulkWorker {
+	if doc.ID == nil {
+		return p.workers[atomic.AddUint32(&p.next, 1)%uint32(len(p.workers))]
+	}
+
+	h := fnv.New32a()
+	fmt.Fprintf(h, "%v/%v", doc.Index, doc.ID)
+	return p.workers[h.Sum32()%uint32(len(p.workers))]
+}
+
+// Flush sends the documents buffered by every worker and waits for them to be sent
+func (p *BulkProcessor) Flush() error {
+	p.RLock()
+	defer p.RUnlock()
+
+	if p.closed {
+		return errors.New("Bulk processor is closed")
+	}
+
+	flushed := make([]chan struct{}, len(p.workers))
+	for i, w := range p.workers {
+		flushed[i] = make(chan struct{})
+		w.items <- bulkItem{flushed: flushed[i]}
+	}
+	for _, f := range flushed {
+		<-f
+	}
+	return nil
+}
+
+// Close sends the remaining documents and stops the workers
+func (p *BulkProcessor) Close() error {
+	p.Lock()
+	if p.closed {
+		p.Unlock()
+		return errors.New("Bulk processor is closed")
+	}
+	p.closed = true
+	for _, w := range p.workers {
+		close(w.items)
+	}
+	p.Unlock()
+
+	p.wg.Wait()
+	return nil
+}
+
+func (w *bulkWorker) run() {
+	defer w.p.wg.Done()
+
+	var tick <-chan time.Time
+	if w.p.config.FlushInterval > 0 {
+		ticker := time.NewTicker(w.p.config.FlushInterval)
+		defer ticker.Stop()
+		tick = ticker.C
+	}
+
+	for {
+		select {
+		case item, ok := <-w.items:
+			if !ok {
+				w.flush()
+				return
+			}
+			if item.flushed != nil {
+				w.flush()
+				close(item.flushed)
+				continue
+			}
+
+			w.documents = append(w.documents, item.document)
+			w.bulkData.Write(item.lines)
+			if w.full() {
+				w.flush()
+			}
+		case <-tick:
+			w.flush()
+		}
+	}
+}
+
+// full reports whether the buffer of the worker hit a threshold
+func (w *bulkWorker) full() bool {
+	config := w.p.config
+	return (config.MaxDocuments > 0 && len(w.documents) >= config.MaxDocuments) ||
+		(config.MaxBytes > 0 && w.bulkData.Len() >= config.MaxBytes)
+}
+
+// flush sends the buffered documents, if any
+func (w *bulkWorker) flush() {
+	if len(w.documents) == 0 {
+		return
+	}
+
+	r := Request{
+		Method:   "POST",
+		API:      "_bulk",
+		BulkData: w.bulkData.Bytes(),
+	}
+	response, err := w.p.client.sendBulk(context.Background(), w.documents, &r)
+	if w.p.config.After != nil {
+		w.p.config.After(w.documents, response, err)
+	}
+
+	w.documents = nil
+	w.bulkData.Reset()
+}
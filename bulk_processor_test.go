@@ -0,0 +1,180 @@
+package goes
+
+import (
+	"bufio"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"time"
+
+	. "github.com/go-check/check"
+)
+
+// bulkRecorder is a fake _bulk endpoint recording the documents it receives
+type bulkRecorder struct {
+	sync.Mutex
+	batches int
+	// values of the "n" field of the indexed documents, by id
+	values map[string][]float64
+}
+
+func (b *bulkRecorder) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	b.Lock()
+	defer b.Unlock()
+
+	b.batches++
+	scanner := bufio.NewScanner(r.Body)
+	for scanner.Scan() {
+		var action map[string]map[string]interface{}
+		json.Unmarshal(scanner.Bytes(), &action)
+		scanner.Scan()
+		var source map[string]float64
+		json.Unmarshal(scanner.Bytes(), &source)
+
+		id, _ := action[BulkCommandIndex]["_id"].(string)
+		b.values[id] = append(b.values[id], source["n"])
+	}
+	w.Write([]byte(`{"errors":false}`))
+}
+
+func (s *GoesTestSuite) TestBulkProcessor(c *C) {
+	recorder := &bulkRecorder{values: map[string][]float64{}}
+	ts := httptest.NewServer(recorder)
+	defer ts.Close()
+
+	conn, err := NewClientFromURL(ts.URL)
+	c.Assert(err, IsNil)
+	conn.WithVersion(ServerVersion{Major: 6, Minor: 8})
+
+	var lock sync.Mutex
+	sent := 0
+	p := conn.NewBulkProcessor(BulkProcessorConfig{
+		MaxDocuments: 10,
+		Workers:      4,
+		After: func(documents []Document, response *Response, err error) {
+			c.Check(err, IsNil)
+			lock.Lock()
+			sent += len(documents)
+			lock.Unlock()
+		},
+	})
+
+	ids := []string{"a", "b", "c", "d", "e"}
+	for n := 0; n < 100; n++ {
+		err := p.Add(Document{
+			Index:       "i",
+			Type:        "t",
+			ID:          ids[n%len(ids)],
+			BulkCommand: BulkCommandIndex,
+			Fields:      map[string]interface{}{"n": n},
+		})
+		c.Assert(err, IsNil)
+	}
+
+	err = p.Close()
+	c.Assert(err, IsNil)
+	c.Assert(sent, Equals, 100)
+
+	for i, id := range ids {
+		values := recorder.values[id]
+		c.Assert(values, HasLen, 20)
+		for j, v := range values {
+			c.Assert(v, Equals, float64(i+j*len(ids)))
+		}
+	}
+
+	c.Assert(p.Add(Document{BulkCommand: BulkCommandIndex}), ErrorMatches, ".* closed")
+	c.Assert(p.Flush(), ErrorMatches, ".* closed")
+}
+
+func (s *GoesTestSuite) TestBulkProcessorFlush(c *C) {
+	recorder := &bulkRecorder{values: map[string][]float64{}}
+	ts := httptest.NewServer(recorder)
+	defer ts.Close()
+
+	conn, err := NewClientFromURL(ts.URL)
+	c.Assert(err, IsNil)
+	conn.WithVersion(ServerVersion{Major: 6, Minor: 8})
+
+	p := conn.NewBulkProcessor(BulkProcessorConfig{MaxBytes: 1 << 20, FlushInterval: time.Hour})
+	defer p.Close()
+
+	for n := 0; n < 3; n++ {
+		p.Add(Document{Index: "i", Type: "t", BulkCommand: BulkCommandIndex, Fields: map[string]interface{}{"n": n}})
+	}
+
+	c.Assert(p.Flush(), IsNil)
+	recorder.Lock()
+	c.Assert(recorder.batches, Equals, 1)
+	c.Assert(recorder.values[""], DeepEquals, []float64{0, 1, 2})
+	recorder.Unlock()
+
+	c.Assert(p.Flush(), IsNil)
+	recorder.Lock()
+	c.Assert(recorder.batches, Equals, 1)
+	recorder.Unlock()
+}
+
+func (s *GoesTestSuite) TestBulkProcessorVersionLookup(c *C) {
+	var lock sync.Mutex
+	lookups := 0
+	recorder := &bulkRecorder{values: map[string][]float64{}}
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/" {
+			lock.Lock()
+			lookups++
+			lock.Unlock()
+			w.Write([]byte(`{"version":{"number":"6.8.0"}}`))
+			return
+		}
+		recorder.ServeHTTP(w, r)
+	}))
+	defer ts.Close()
+
+	conn, err := NewClientFromURL(ts.URL)
+	c.Assert(err, IsNil)
+
+	p := conn.NewBulkProcessor(BulkProcessorConfig{MaxBytes: 1 << 20, FlushInterval: time.Hour})
+	defer p.Close()
+
+	for n := 0; n < 3; n++ {
+		c.Assert(p.Add(Document{Index: "i", Type: "t", BulkCommand: BulkCommandIndex, Fields: map[string]interface{}{"n": n}}), IsNil)
+	}
+	c.Assert(p.Flush(), IsNil)
+
+	recorder.Lock()
+	c.Assert(recorder.values[""], DeepEquals, []float64{0, 1, 2})
+	recorder.Unlock()
+	lock.Lock()
+	c.Assert(lookups, Equals, 1)
+	lock.Unlock()
+}
+
+func (s *GoesTestSuite) TestBulkProcessorInterval(c *C) {
+	recorder := &bulkRecorder{values: map[string][]float64{}}
+	ts := httptest.NewServer(recorder)
+	defer ts.Close()
+
+	conn, err := NewClientFromURL(ts.URL)
+	c.Assert(err, IsNil)
+	conn.WithVersion(ServerVersion{Major: 6, Minor: 8})
+
+	flushed := make(chan int, 1)
+	p := conn.NewBulkProcessor(BulkProcessorConfig{
+		FlushInterval: 10 * time.Millisecond,
+		After: func(documents []Document, response *Response, err error) {
+			flushed <- len(documents)
+		},
+	})
+	defer p.Close()
+
+	p.Add(Document{Index: "i", Type: "t", ID: "1", BulkCommand: BulkCommandIndex, Fields: map[string]interface{}{"n": 1}})
+
+	select {
+	case n := <-flushed:
+		c.Assert(n, Equals, 1)
+	case <-time.After(time.Second):
+		c.Fatal("documents were not flushed")
+	}
+}
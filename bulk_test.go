@@ -0,0 +1,576 @@
+package goes
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	. "github.com/go-check/check"
+)
+
+var bulkErrorResponses = []struct {
+	version string
+	body    string
+}{
+	{
+		"1.7",
+		`{"took":3,"errors":true,"items":[
+			{"index":{"_index":"i","_type":"t","_id":"1","_version":1,"status":201}},
+			{"create":{"_index":"i","_type":"t","_id":"2","status":409,"error":"DocumentAlreadyExistsException[[i][2] [t][2]: document already exists]"}},
+			{"index":{"_index":"i","_type":"t","_id":"3","status":400,"error":"MapperParsingException[failed to parse [n]]"}}
+		]}`,
+	},
+	{
+		"7.10",
+		`{"took":3,"errors":true,"items":[
+			{"index":{"_index":"i","_type":"t","_id":"1","_version":1,"result":"created","status":201}},
+			{"create":{"_index":"i","_type":"t","_id":"2","status":409,"error":{"type":"DocumentAlreadyExistsException","reason":"[i][2] [t][2]: document already exists","index":"i"}}},
+			{"index":{"_index":"i","_type":"t","_id":"3","status":400,"error":{"type":"MapperParsingException","reason":"failed to parse [n]","caused_by":{"type":"number_format_exception","reason":"For input string: \"foo\""}}}}
+		]}`,
+	},
+}
+
+func (s *GoesTestSuite) TestBulkError(c *C) {
+	documents := []Document{
+		{Index: "i", Type: "t", ID: "1", BulkCommand: BulkCommandIndex, Fields: map[string]interface{}{"n": 1}},
+		{Index: "i", Type: "t", ID: "2", BulkCommand: "create", Fields: map[string]interface{}{"n": 2}},
+		{Index: "i", Type: "t", ID: "3", BulkCommand: BulkCommandIndex, Fields: map[string]interface{}{"n": "foo"}},
+	}
+
+	for _, t := range bulkErrorResponses {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(t.body))
+		}))
+
+		conn, err := NewClientFromURL(ts.URL)
+		c.Assert(err, IsNil)
+		version, err := ParseServerVersion(t.version)
+		c.Assert(err, IsNil)
+		conn.WithVersion(version)
+
+		resp, err := conn.BulkSend(documents)
+		ts.Close()
+
+		c.Assert(resp.Items, HasLen, 3, Commentf("version %s", t.version))
+		c.Assert(err, DeepEquals, &BulkError{
+			Succeeded: []BulkItemResult{
+				{Position: 0, Action: "index", Index: "i", Type: "t", ID: "1", Status: 201, Document: documents[0]},
+			},
+			Failed: []BulkItemResult{
+				{Position: 1, Action: "create", Index: "i", Type: "t", ID: "2", Status: 409,
+					ErrorType: "DocumentAlreadyExistsException", ErrorReason: "[i][2] [t][2]: document already exists", Document: documents[1]},
+				{Position: 2, Action: "index", Index: "i", Type: "t", ID: "3", Status: 400,
+					ErrorType: "MapperParsingException", ErrorReason: "failed to parse [n]", Document: documents[2]},
+			},
+		}, Commentf("version %s", t.version))
+		c.Assert(err, ErrorMatches, `2 of 3 bulk items failed, first failure at position 1: \[409\] DocumentAlreadyExistsException: .*`)
+	}
+}
+
+func (s *GoesTestSuite) TestItemCause(c *C) {
+	var item Item
+	err := item.UnmarshalJSON([]byte(`{"_id":"3","status":400,"error":{"type":"mapper_parsing_exception","reason":"failed to parse","caused_by":{"type":"number_format_exception","reason":"For input string"}}}`))
+	c.Assert(err, IsNil)
+	c.Assert(item.Error, Matches, `\{"type":"mapper_parsing_exception".*`)
+	c.Assert(item.Cause, DeepEquals, &ErrorCause{
+		Type:     "mapper_parsing_exception",
+		Reason:   "failed to parse",
+		CausedBy: &ErrorCause{Type: "number_format_exception", Reason: "For input string"},
+	})
+
+	item = Item{}
+	err = item.UnmarshalJSON([]byte(`{"_id":"1","status":500,"error":"something went wrong"}`))
+	c.Assert(err, IsNil)
+	c.Assert(item.Error, Equals, "something went wrong")
+	c.Assert(item.Cause, DeepEquals, &ErrorCause{Reason: "something went wrong"})
+}
+
+func (s *GoesTestSuite) TestBulkSendRetry(c *C) {
+	// Document 1 is rejected twice, document 2 always and document 3 is invalid
+	attempts := map[string]int{}
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		items := []string{}
+		scanner := bufio.NewScanner(r.Body)
+		for scanner.Scan() {
+			var action map[string]map[string]string
+			json.Unmarshal(scanner.Bytes(), &action)
+			scanner.Scan()
+
+			id := action[BulkCommandIndex]["_id"]
+			attempts[id]++
+			switch {
+			case id == "1" && attempts[id] > 2, id == "0":
+				items = append(items, fmt.Sprintf(`{"index":{"_id":"%s","status":201}}`, id))
+			case id == "3":
+				items = append(items, fmt.Sprintf(`{"index":{"_id":"%s","status":400,"error":{"type":"mapper_parsing_exception","reason":"failed to parse"}}}`, id))
+			default:
+				items = append(items, fmt.Sprintf(`{"index":{"_id":"%s","status":429,"error":{"type":"es_rejected_execution_exception","reason":"rejected"}}}`, id))
+			}
+		}
+		fmt.Fprintf(w, `{"errors":true,"items":[%s]}`, strings.Join(items, ","))
+	}))
+	defer ts.Close()
+
+	conn, err := NewClientFromURL(ts.URL)
+	c.Assert(err, IsNil)
+	conn.WithVersion(ServerVersion{Major: 6, Minor: 8})
+
+	documents := []Document{}
+	for i := 0; i < 4; i++ {
+		documents = append(documents, Document{
+			Index:       "i",
+			Type:        "t",
+			ID:          strconv.Itoa(i),
+			BulkCommand: BulkCommandIndex,
+			Fields:      map[string]interface{}{"n": i},
+		})
+	}
+
+	policy := DefaultRetryPolicy
+	policy.Backoff = time.Millisecond
+	policy.MaxAttempts = 4
+
+	resp, err := conn.BulkSendRetry(documents, &policy)
+	c.Assert(attempts, DeepEquals, map[string]int{"0": 1, "1": 3, "2": 4, "3": 1})
+
+	c.Assert(resp.Errors, Equals, true)
+	c.Assert(resp.Items, HasLen, 4)
+	for i, item := range resp.Items {
+		c.Assert(item[BulkCommandIndex].ID, Equals, strconv.Itoa(i))
+	}
+
+	bulkErr, ok := err.(*BulkError)
+	c.Assert(ok, Equals, true)
+	c.Assert(bulkErr.Succeeded, HasLen, 2)
+	c.Assert(bulkErr.Failed, HasLen, 2)
+	c.Assert(bulkErr.Failed[0].Position, Equals, 2)
+	c.Assert(bulkErr.Failed[0].Status, Equals, uint64(429))
+	c.Assert(bulkErr.Failed[0].Document, DeepEquals, documents[2])
+	c.Assert(bulkErr.Failed[1].Position, Equals, 3)
+	c.Assert(bulkErr.Failed[1].ErrorType, Equals, "mapper_parsing_exception")
+}
+
+func (s *GoesTestSuite) TestBulkSendRetryFailure(c *C) {
+	// Document 0 is indexed and document 1 rejected, the retry failing
+	requests := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if requests > 1 {
+			w.WriteHeader(http.StatusInternalServerError)
+			w.Write([]byte(`{"error":"unavailable","status":500}`))
+			return
+		}
+		w.Write([]byte(`{"errors":true,"items":[` +
+			`{"index":{"_id":"0","status":201}},` +
+			`{"index":{"_id":"1","status":429,"error":{"type":"es_rejected_execution_exception","reason":"rejected"}}}]}`))
+	}))
+	defer ts.Close()
+
+	conn, err := NewClientFromURL(ts.URL)
+	c.Assert(err, IsNil)
+
+	documents := []Document{
+		{Index: "i", ID: "0", BulkCommand: BulkCommandIndex, Fields: map[string]interface{}{"n": 0}},
+		{Index: "i", ID: "1", BulkCommand: BulkCommandIndex, Fields: map[string]interface{}{"n": 1}},
+	}
+	resp, err := conn.BulkSendRetry(documents, &RetryPolicy{MaxAttempts: 3, Backoff: time.Millisecond, StatusCodes: []uint64{429}})
+	c.Assert(err, NotNil)
+	_, ok := err.(*BulkError)
+	c.Assert(ok, Equals, false)
+	c.Assert(requests, Equals, 2)
+
+	c.Assert(resp.Errors, Equals, true)
+	c.Assert(resp.Items, HasLen, 2)
+	c.Assert(resp.Items[0][BulkCommandIndex].Status, Equals, uint64(201))
+	c.Assert(resp.Items[1][BulkCommandIndex].Status, Equals, uint64(429))
+}
+
+func (s *GoesTestSuite) TestEncodeBulkDocument(c *C) {
+	version := int64(3)
+	seqNo := int64(0)
+	primaryTerm := int64(1)
+
+	documents := []struct {
+		doc      Document
+		version  string
+		expected string
+	}{
+		{
+			Document{Index: "i", Type: "t", ID: "1", BulkCommand: BulkCommandCreate, Pipeline: "p", Fields: map[string]interface{}{"n": 1}},
+			"",
+			`{"create":{"_id":"1","_index":"i","_type":"t","pipeline":"p"}}` + "\n" + `{"n":1}` + "\n",
+		},
+		{
+			Document{Index: "i", Type: "t", ID: "1", BulkCommand: BulkCommandIndex, Routing: "r", Version: &version, VersionType: "external", Fields: map[string]interface{}{"n": 1}},
+			"6.8.0",
+			`{"index":{"_id":"1","_index":"i","_routing":"r","_type":"t","_version":3,"_version_type":"external"}}` + "\n" + `{"n":1}` + "\n",
+		},
+		{
+			Document{Index: "i", Type: "t", ID: "1", BulkCommand: BulkCommandIndex, Routing: "r", Version: &version, VersionType: "external", Fields: map[string]interface{}{"n": 1}},
+			"7.10.2",
+			`{"index":{"_id":"1","_index":"i","routing":"r","version":3,"version_type":"external"}}` + "\n" + `{"n":1}` + "\n",
+		},
+		{
+			Document{Index: "i", Type: "t", ID: "1", BulkCommand: BulkCommandUpdate, RetryOnConflict: 3, Fields: map[string]interface{}{"n": 1}, DocAsUpsert: true},
+			"7.10.2",
+			`{"update":{"_id":"1","_index":"i","retry_on_conflict":3}}` + "\n" + `{"doc":{"n":1},"doc_as_upsert":true}` + "\n",
+		},
+		{
+			Document{Index: "i", Type: "t", ID: "1", BulkCommand: BulkCommandUpdate, IfSeqNo: &seqNo, IfPrimaryTerm: &primaryTerm,
+				Script: map[string]interface{}{"source": "ctx._source.n += 1"}, Upsert: map[string]interface{}{"n": 0}},
+			"",
+			`{"update":{"_id":"1","_index":"i","_type":"t","if_primary_term":1,"if_seq_no":0}}` + "\n" + `{"script":{"source":"ctx._source.n += 1"},"upsert":{"n":0}}` + "\n",
+		},
+		{
+			Document{Index: "i", Type: "t", ID: "1", BulkCommand: BulkCommandDelete, Routing: "r", Fields: map[string]interface{}{"n": 1}},
+			"5.6.0",
+			`{"delete":{"_id":"1","_index":"i","_routing":"r","_type":"t"}}` + "\n",
+		},
+	}
+
+	for _, d := range documents {
+		// The version is not needed for documents without metadata
+		version, _ := ParseServerVersion(d.version)
+		var buf bytes.Buffer
+		c.Assert(encodeBulkDocument(&buf, d.doc, version), IsNil)
+		c.Assert(buf.String(), Equals, d.expected)
+	}
+}
+
+func (s *GoesTestSuite) TestBulkSendVersion(c *C) {
+	var bulkData string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/" {
+			w.Write([]byte(`{"version":{"number":"7.10.2"}}`))
+			return
+		}
+		body, _ := ioutil.ReadAll(r.Body)
+		bulkData = string(body)
+		w.Write([]byte(`{"errors":false}`))
+	}))
+	defer ts.Close()
+
+	conn, err := NewClientFromURL(ts.URL)
+	c.Assert(err, IsNil)
+
+	_, err = conn.BulkSend([]Document{{Index: "i", Type: "t", ID: "1", BulkCommand: BulkCommandDelete, Routing: "r"}})
+	c.Assert(err, IsNil)
+	c.Assert(bulkData, Equals, `{"delete":{"_id":"1","_index":"i","routing":"r"}}`+"\n")
+}
+
+func (s *GoesTestSuite) TestBulkRequest(c *C) {
+	documents := []Document{
+		{Index: "i", Type: "t", ID: "1", BulkCommand: BulkCommandIndex, Fields: map[string]interface{}{"n": 1}},
+		{Index: "i", Type: "t", ID: "2", BulkCommand: BulkCommandDelete},
+	}
+	expected := `{"index":{"_id":"1","_index":"i","_type":"t"}}` + "\n" + `{"n":1}` + "\n" +
+		`{"delete":{"_id":"2","_index":"i","_type":"t"}}` + "\n"
+
+	r := &BulkRequest{Documents: documents, ExtraArgs: url.Values{"refresh": {"true"}}}
+	req, err := r.Request()
+	c.Assert(err, IsNil)
+	c.Assert(req.Method, Equals, "POST")
+	c.Assert(req.URL.String(), Equals, "/_bulk?refresh=true")
+	c.Assert(req.Header.Get("Content-Type"), Equals, "application/x-ndjson")
+	c.Assert(req.ContentLength, Equals, int64(-1))
+
+	body, err := ioutil.ReadAll(req.Body)
+	c.Assert(err, IsNil)
+	c.Assert(string(body), Equals, expected)
+
+	again, err := req.GetBody()
+	c.Assert(err, IsNil)
+	body, err = ioutil.ReadAll(again)
+	c.Assert(err, IsNil)
+	c.Assert(string(body), Equals, expected)
+}
+
+func (s *GoesTestSuite) TestBulkSendEncodingError(c *C) {
+	var lock sync.Mutex
+	requests := 0
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		lock.Lock()
+		requests++
+		lock.Unlock()
+		ioutil.ReadAll(r.Body)
+		w.Write([]byte(`{"errors":false}`))
+	})
+	ts1 := httptest.NewServer(handler)
+	defer ts1.Close()
+	ts2 := httptest.NewServer(handler)
+	defer ts2.Close()
+
+	conn := NewClusterClient([]string{ts1.Listener.Addr().String(), ts2.Listener.Addr().String()})
+	conn.WithVersion(ServerVersion{Major: 6, Minor: 8})
+	conn.WithRetryPolicy(&RetryPolicy{MaxAttempts: 3, Backoff: time.Millisecond})
+
+	_, err := conn.BulkSend([]Document{
+		{Index: "i", Type: "t", ID: "1", BulkCommand: BulkCommandIndex, Fields: map[string]interface{}{"n": 1}},
+		{Index: "i", Type: "t", ID: "2", BulkCommand: BulkCommandIndex, Fields: "n"},
+	})
+	c.Assert(err, ErrorMatches, "Document fields not in struct or map.*")
+	lock.Lock()
+	c.Assert(requests <= 1, Equals, true)
+	lock.Unlock()
+	for _, state := range conn.NodeStates() {
+		c.Assert(state.Alive, Equals, true)
+	}
+}
+
+// bulkSplitServer answers _bulk requests with an item per document, failing
+// the document with id "5", and records the size of the requests
+type bulkSplitServer struct {
+	sync.Mutex
+	documents []int
+	sizes     []int
+}
+
+func (b *bulkSplitServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, _ := ioutil.ReadAll(r.Body)
+	items := []string{}
+	scanner := bufio.NewScanner(bytes.NewReader(body))
+	for scanner.Scan() {
+		var action map[string]map[string]string
+		json.Unmarshal(scanner.Bytes(), &action)
+		scanner.Scan()
+
+		id := action[BulkCommandIndex]["_id"]
+		if id == "5" {
+			items = append(items, `{"index":{"_id":"5","status":400,"error":{"type":"mapper_parsing_exception","reason":"failed to parse"}}}`)
+		} else {
+			items = append(items, fmt.Sprintf(`{"index":{"_id":"%s","status":201}}`, id))
+		}
+	}
+
+	b.Lock()
+	b.documents = append(b.documents, len(items))
+	b.sizes = append(b.sizes, len(body))
+	b.Unlock()
+
+	fmt.Fprintf(w, `{"took":1,"errors":%t,"items":[%s]}`, strings.Contains(string(body), `"_id":"5"`), strings.Join(items, ","))
+}
+
+func (s *GoesTestSuite) TestBulkSendSplit(c *C) {
+	documents := []Document{}
+	for i := 0; i < 10; i++ {
+		documents = append(documents, Document{
+			Index:       "i",
+			Type:        "t",
+			ID:          strconv.Itoa(i),
+			BulkCommand: BulkCommandIndex,
+			Fields:      map[string]interface{}{"n": i},
+		})
+	}
+
+	configs := []struct {
+		config    BulkSplitConfig
+		documents []int
+	}{
+		{BulkSplitConfig{}, []int{10}},
+		{BulkSplitConfig{MaxDocuments: 3}, []int{3, 3, 3, 1}},
+		{BulkSplitConfig{MaxDocuments: 3, Concurrency: 4}, []int{3, 3, 3, 1}},
+		// Each document takes 58 bytes
+		{BulkSplitConfig{MaxBytes: 200}, []int{3, 3, 3, 1}},
+		{BulkSplitConfig{MaxBytes: 200, MaxDocuments: 2}, []int{2, 2, 2, 2, 2}},
+		{BulkSplitConfig{MaxBytes: 10}, []int{1, 1, 1, 1, 1, 1, 1, 1, 1, 1}},
+	}
+
+	for _, t := range configs {
+		server := &bulkSplitServer{}
+		ts := httptest.NewServer(server)
+
+		conn, err := NewClientFromURL(ts.URL)
+		c.Assert(err, IsNil)
+		conn.WithVersion(ServerVersion{Major: 6, Minor: 8})
+
+		resp, err := conn.BulkSendSplit(documents, t.config)
+		ts.Close()
+
+		sort.Ints(server.documents)
+		sort.Ints(t.documents)
+		c.Assert(server.documents, DeepEquals, t.documents, Commentf("%+v", t.config))
+		if t.config.MaxBytes > 0 {
+			for _, size := range server.sizes {
+				c.Assert(size <= t.config.MaxBytes || t.config.MaxBytes < 58, Equals, true)
+			}
+		}
+
+		c.Assert(resp.Errors, Equals, true)
+		c.Assert(resp.Took, Equals, uint64(len(t.documents)))
+		c.Assert(resp.Items, HasLen, 10)
+		for i, item := range resp.Items {
+			c.Assert(item[BulkCommandIndex].ID, Equals, strconv.Itoa(i))
+		}
+
+		bulkErr, ok := err.(*BulkError)
+		c.Assert(ok, Equals, true)
+		c.Assert(bulkErr.Succeeded, HasLen, 9)
+		c.Assert(bulkErr.Failed, HasLen, 1)
+		c.Assert(bulkErr.Failed[0].Position, Equals, 5)
+		c.Assert(bulkErr.Failed[0].Document, DeepEquals, documents[5])
+	}
+}
+
+func (s *GoesTestSuite) TestBulkSendSplitError(c *C) {
+	var lock sync.Mutex
+	requests := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		lock.Lock()
+		requests++
+		lock.Unlock()
+		w.WriteHeader(http.StatusRequestEntityTooLarge)
+		w.Write([]byte(`{"error":"request too large","status":413}`))
+	}))
+	defer ts.Close()
+
+	conn, err := NewClientFromURL(ts.URL)
+	c.Assert(err, IsNil)
+	conn.WithVersion(ServerVersion{Major: 6, Minor: 8})
+
+	documents := []Document{}
+	for i := 0; i < 10; i++ {
+		documents = append(documents, Document{Index: "i", Type: "t", BulkCommand: BulkCommandIndex, Fields: map[string]interface{}{"n": i}})
+	}
+
+	_, err = conn.BulkSendSplit(documents, BulkSplitConfig{MaxDocuments: 1})
+	c.Assert(err, ErrorMatches, `\[413\] request too large`)
+	c.Assert(requests, Equals, 1)
+}
+
+func (s *GoesTestSuite) TestBulkSendSplitPartialError(c *C) {
+	// The first two requests succeed, the third is rejected
+	var lock sync.Mutex
+	requests := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		lock.Lock()
+		requests++
+		n := requests
+		lock.Unlock()
+		if n > 2 {
+			w.WriteHeader(http.StatusRequestEntityTooLarge)
+			w.Write([]byte(`{"error":"request too large","status":413}`))
+			return
+		}
+		fmt.Fprintf(w, `{"took":1,"errors":false,"items":[{"index":{"_id":"%d","status":201}}]}`, n-1)
+	}))
+	defer ts.Close()
+
+	conn, err := NewClientFromURL(ts.URL)
+	c.Assert(err, IsNil)
+
+	documents := []Document{}
+	for i := 0; i < 4; i++ {
+		documents = append(documents, Document{Index: "i", ID: strconv.Itoa(i), BulkCommand: BulkCommandIndex, Fields: map[string]interface{}{"n": i}})
+	}
+
+	resp, err := conn.BulkSendSplit(documents, BulkSplitConfig{MaxDocuments: 1})
+	c.Assert(err, ErrorMatches, `\[413\] request too large`)
+
+	c.Assert(resp.Errors, Equals, true)
+	c.Assert(resp.Took, Equals, uint64(2))
+	c.Assert(resp.Items, HasLen, 4)
+	for i, item := range resp.Items {
+		c.Assert(item[BulkCommandIndex].ID, Equals, strconv.Itoa(i))
+	}
+	c.Assert(resp.Items[0][BulkCommandIndex].Status, Equals, uint64(201))
+	c.Assert(resp.Items[1][BulkCommandIndex].Status, Equals, uint64(201))
+	c.Assert(resp.Items[2][BulkCommandIndex].Error, Equals, err.Error())
+	c.Assert(resp.Items[3][BulkCommandIndex].Error, Equals, err.Error())
+}
+
+// joinBulkDocuments builds a bulk body the way BulkSend used to, marshalling
+// every line on its own before joining them
+func joinBulkDocuments(documents []Document) ([]byte, error) {
+	bulkData := make([][]byte, 0, len(documents)*2+1)
+	for _, doc := range documents {
+		action, err := json.Marshal(map[string]interface{}{
+			doc.BulkCommand: map[string]interface{}{
+				"_index": doc.Index,
+				"_type":  doc.Type,
+				"_id":    doc.ID,
+			},
+		})
+		if err != nil {
+			return nil, err
+		}
+		bulkData = append(bulkData, action)
+
+		sources, err := json.Marshal(doc.Fields)
+		if err != nil {
+			return nil, err
+		}
+		bulkData = append(bulkData, sources)
+	}
+	bulkData = append(bulkData, []byte(nil))
+
+	return bytes.Join(bulkData, []byte("\n")), nil
+}
+
+func benchmarkDocuments() []Document {
+	documents := make([]Document, 10000)
+	for i := range documents {
+		documents[i] = Document{
+			Index:       "i",
+			Type:        "t",
+			ID:          strconv.Itoa(i),
+			BulkCommand: BulkCommandIndex,
+			Fields:      map[string]interface{}{"n": i, "message": strings.Repeat("foo ", 50)},
+		}
+	}
+	return documents
+}
+
+func BenchmarkBulkJoin(b *testing.B) {
+	documents := benchmarkDocuments()
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		if _, err := joinBulkDocuments(documents); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkBulkBuffer(b *testing.B) {
+	documents := benchmarkDocuments()
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		var buf bytes.Buffer
+		for _, doc := range documents {
+			if err := encodeBulkDocument(&buf, doc, ServerVersion{}); err != nil {
+				b.Fatal(err)
+			}
+		}
+	}
+}
+
+func BenchmarkBulkStream(b *testing.B) {
+	documents := benchmarkDocuments()
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		req, err := (&BulkRequest{Documents: documents}).Request()
+		if err != nil {
+			b.Fatal(err)
+		}
+		if _, err := io.Copy(ioutil.Discard, req.Body); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,131 @@
+package goes
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"sync"
+)
+
+var gzipWriters = sync.Pool{
+	New: func() interface{} {
+		return gzip.NewWriter(ioutil.Discard)
+	},
+}
+
+// WithGzip enables gzip compression. Request bodies of at least threshold
+// bytes are compressed and the server is asked to compress its responses,
+// which are transparently decompressed. Returns the original client.
+func (c *Client) WithGzip(threshold int) *Client {
+	c.gzip = true
+	c.gzipThreshold = threshold
+	return c
+}
+
+// compress asks for a compressed response and compresses the body of req when
+// gzip compression is enabled and the body is large enough
+func (c *Client) compress(req *http.Request) error {
+	if !c.gzip {
+		return nil
+	}
+	req.Header.Set("Accept-Encoding", "gzip")
+
+	if req.ContentLength < 0 {
+		return c.compressStream(req)
+	}
+
+	body, err := requestBody(req)
+	if err != nil {
+		return err
+	}
+	if len(body) == 0 || len(body) < c.gzipThreshold {
+		return nil
+	}
+
+	var buf bytes.Buffer
+	zw := gzipWriters.Get().(*gzip.Writer)
+	defer gzipWriters.Put(zw)
+	zw.Reset(&buf)
+	if _, err := zw.Write(body); err != nil {
+		return err
+	}
+	if err := zw.Close(); err != nil {
+		return err
+	}
+
+	compressed := buf.Bytes()
+	req.Body = ioutil.NopCloser(bytes.NewReader(compressed))
+	req.ContentLength = int64(len(compressed))
+	req.GetBody = func() (io.ReadCloser, error) {
+		return ioutil.NopCloser(bytes.NewReader(compressed)), nil
+	}
+	req.Header.Set("Content-Encoding", "gzip")
+	return nil
+}
+
+// compressStream compresses the body of req while it is sent when the body
+// is of unknown length. Only the first threshold bytes are read ahead to find
+// out whether it is large enough.
+func (c *Client) compressStream(req *http.Request) error {
+	head := make([]byte, c.gzipThreshold)
+	n, err := io.ReadFull(req.Body, head)
+	if err == io.EOF || err == io.ErrUnexpectedEOF {
+		// The whole body is smaller than the threshold
+		req.Body.Close()
+		head = head[:n]
+		req.Body = ioutil.NopCloser(bytes.NewReader(head))
+		req.ContentLength = int64(n)
+		req.GetBody = func() (io.ReadCloser, error) {
+			return ioutil.NopCloser(bytes.NewReader(head)), nil
+		}
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	body := req.Body
+	req.Body = gzipStream(io.MultiReader(bytes.NewReader(head), body), body)
+	if getBody := req.GetBody; getBody != nil {
+		req.GetBody = func() (io.ReadCloser, error) {
+			body, err := getBody()
+			if err != nil {
+				return nil, err
+			}
+			return gzipStream(body, body), nil
+		}
+	}
+	req.Header.Set("Content-Encoding", "gzip")
+	return nil
+}
+
+// gzipStream returns a reader of the compressed content of r. closer is
+// closed once r was read or the returned reader was closed.
+func gzipStream(r io.Reader, closer io.Closer) io.ReadCloser {
+	pr, pw := io.Pipe()
+	go func() {
+		zw := gzipWriters.Get().(*gzip.Writer)
+		defer gzipWriters.Put(zw)
+		zw.Reset(pw)
+
+		_, err := io.Copy(zw, r)
+		if err == nil {
+			err = zw.Close()
+		}
+		closer.Close()
+		pw.CloseWithError(err)
+	}()
+	return pr
+}
+
+// decompress wraps the body of resp into a gzip reader if it is compressed.
+// The http client only hands over compressed bodies when the Accept-Encoding
+// header was set by compress, it decompresses them by itself otherwise.
+func decompress(resp *http.Response) (io.Reader, error) {
+	if resp.Header.Get("Content-Encoding") != "gzip" || resp.Body == http.NoBody {
+		return resp.Body, nil
+	}
+	return gzip.NewReader(resp.Body)
+}
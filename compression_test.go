@@ -0,0 +1,76 @@
+package goes
+
+import (
+	"compress/gzip"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+
+	. "github.com/go-check/check"
+)
+
+// newGzipServer starts a test server accepting gzip compressed bodies and
+// compressing its responses when asked to
+func newGzipServer(c *C, compressed *bool) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*compressed = r.Header.Get("Content-Encoding") == "gzip"
+
+		body := r.Body
+		if *compressed {
+			zr, err := gzip.NewReader(r.Body)
+			c.Assert(err, IsNil)
+			body = zr
+		}
+		data, err := ioutil.ReadAll(body)
+		c.Check(err, IsNil)
+		c.Check(strings.HasSuffix(string(data), "\n"), Equals, true)
+
+		response := []byte(`{"took":1,"errors":false}`)
+		if r.Header.Get("Accept-Encoding") != "gzip" {
+			w.Write(response)
+			return
+		}
+		w.Header().Set("Content-Encoding", "gzip")
+		zw := gzip.NewWriter(w)
+		zw.Write(response)
+		zw.Close()
+	}))
+}
+
+func (s *GoesTestSuite) TestGzip(c *C) {
+	var compressed bool
+	ts := newGzipServer(c, &compressed)
+	defer ts.Close()
+
+	conn, err := NewClientFromURL(ts.URL)
+	c.Assert(err, IsNil)
+	conn.WithVersion(ServerVersion{Major: 6, Minor: 8})
+	// Make sure responses are decompressed by the client, not by the transport
+	conn.WithHTTPClient(&http.Client{Transport: &http.Transport{DisableCompression: true}})
+
+	docs := []Document{{
+		Index:       "i",
+		Type:        "t",
+		ID:          "1",
+		BulkCommand: BulkCommandIndex,
+		Fields:      map[string]interface{}{"message": strings.Repeat("foo ", 100)},
+	}}
+
+	response, err := conn.BulkSend(docs)
+	c.Assert(err, IsNil)
+	c.Assert(compressed, Equals, false)
+	c.Assert(response.Took, Equals, uint64(1))
+
+	conn.WithGzip(1024)
+	response, err = conn.BulkSend(docs)
+	c.Assert(err, IsNil)
+	c.Assert(compressed, Equals, false)
+	c.Assert(response.Took, Equals, uint64(1))
+
+	docs[0].Fields = map[string]interface{}{"message": strings.Repeat("foo ", 1000)}
+	response, err = conn.BulkSend(docs)
+	c.Assert(err, IsNil)
+	c.Assert(compressed, Equals, true)
+	c.Assert(response.Took, Equals, uint64(1))
+}
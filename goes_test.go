@@ -6,6 +6,7 @@ package goes
 
 import (
 	"net/http"
+	"net/http/httptest"
 	"net/url"
 	"os"
 	"strings"
@@ -41,7 +42,7 @@ func (s *GoesTestSuite) SetUpTest(c *C) {
 
 func (s *GoesTestSuite) TestNewClient(c *C) {
 	conn := NewClient(ESHost, ESPort)
-	c.Assert(conn, DeepEquals, &Client{ESHost, ESPort, http.DefaultClient, ""})
+	c.Assert(conn, DeepEquals, &Client{Host: ESHost, Port: ESPort, Client: http.DefaultClient})
 }
 
 func (s *GoesTestSuite) TestWithHTTPClient(c *C) {
@@ -54,11 +55,55 @@ func (s *GoesTestSuite) TestWithHTTPClient(c *C) {
 	}
 	conn := NewClient(ESHost, ESPort).WithHTTPClient(cl)
 
-	c.Assert(conn, DeepEquals, &Client{ESHost, ESPort, cl, ""})
+	c.Assert(conn, DeepEquals, &Client{Host: ESHost, Port: ESPort, Client: cl})
 	c.Assert(conn.Client.Transport.(*http.Transport).DisableCompression, Equals, true)
 	c.Assert(conn.Client.Transport.(*http.Transport).ResponseHeaderTimeout, Equals, 1*time.Second)
 }
 
+func (s *GoesTestSuite) TestNewClientFromURL(c *C) {
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user, password, ok := r.BasicAuth()
+		c.Check(ok, Equals, true)
+		c.Check(user, Equals, "user")
+		c.Check(password, Equals, "pass")
+		c.Check(r.URL.Path, Equals, "/es/i/_search")
+		w.Write([]byte(`{"took":1}`))
+	}))
+	defer ts.Close()
+
+	conn, err := NewClientFromURL(strings.Replace(ts.URL, "https://", "https://user:pass@", 1) + "/es/")
+	c.Assert(err, IsNil)
+	conn.WithHTTPClient(ts.Client())
+
+	response, err := conn.Search(map[string]interface{}{}, []string{"i"}, nil, nil)
+	c.Assert(err, IsNil)
+	c.Assert(response.Took, Equals, uint64(1))
+	c.Assert(conn.NodeStates()[0].URL, Equals, strings.Replace(ts.URL, "https://", "https://user:xxxxx@", 1)+"/es")
+
+	_, err = NewClientFromURL("ftp://localhost:9200")
+	c.Assert(err, ErrorMatches, "Unsupported scheme .*")
+
+	_, err = NewClientFromURL("localhost:9200")
+	c.Assert(err, NotNil)
+}
+
+func (s *GoesTestSuite) TestNewClientFromEnv(c *C) {
+	os.Setenv("TEST_GOES_ELASTICSEARCH_URL", "http://es1:9200, https://es2:9243/prefix")
+	defer os.Unsetenv("TEST_GOES_ELASTICSEARCH_URL")
+
+	conn, err := NewClientFromEnv("TEST_GOES_ELASTICSEARCH_URL")
+	c.Assert(err, IsNil)
+	c.Assert(conn.Host, Equals, "es1")
+	c.Assert(conn.Port, Equals, "9200")
+	c.Assert(conn.NodeStates(), DeepEquals, []NodeState{
+		{URL: "http://es1:9200", Alive: true},
+		{URL: "https://es2:9243/prefix", Alive: true},
+	})
+
+	_, err = NewClientFromEnv("TEST_GOES_UNSET_ELASTICSEARCH_URL")
+	c.Assert(err, ErrorMatches, "Environment variable .* is not set")
+}
+
 func (s *GoesTestSuite) TestUrl(c *C) {
 	r := Request{
 		Query:     "q",
@@ -572,7 +617,7 @@ func (s *GoesTestSuite) TestDeleteByQuery(c *C) {
 	docID := "1234"
 
 	conn := NewClient(ESHost, ESPort)
-	version, _ := conn.Version()
+	version, _ := conn.ServerVersion()
 
 	// just in case
 	conn.DeleteIndex(indexName)
@@ -616,7 +661,7 @@ func (s *GoesTestSuite) TestDeleteByQuery(c *C) {
 	response, err = conn.DeleteByQuery(query, []string{indexName}, []string{docType}, url.Values{})
 
 	// There's no delete by query in ES 2.x
-	if version > "2" && version < "5" {
+	if !version.Supports(SupportsDeleteByQuery) {
 		c.Assert(err, ErrorMatches, ".* does not support delete by query")
 		return
 	}
@@ -655,7 +700,7 @@ func (s *GoesTestSuite) TestGet(c *C) {
 	}
 
 	conn := NewClient(ESHost, ESPort)
-	version, _ := conn.Version()
+	version, _ := conn.ServerVersion()
 	conn.DeleteIndex(indexName)
 
 	_, err := conn.CreateIndex(indexName, map[string]interface{}{})
@@ -686,6 +731,7 @@ func (s *GoesTestSuite) TestGet(c *C) {
 	}
 
 	response.Raw = nil
+	response.RawSource = nil
 	c.Assert(response, DeepEquals, expectedResponse)
 
 	expectedResponse = &Response{
@@ -702,7 +748,7 @@ func (s *GoesTestSuite) TestGet(c *C) {
 
 	fields := make(url.Values, 1)
 	// The fields param is no longer supported in ES 5.x
-	if version < "5" {
+	if version.Before(5, 0) {
 		fields.Set("fields", "f1")
 	} else {
 		expectedResponse.Source = map[string]interface{}{"f1": "foo"}
@@ -713,6 +759,7 @@ func (s *GoesTestSuite) TestGet(c *C) {
 	c.Assert(err, IsNil)
 
 	response.Raw = nil
+	response.RawSource = nil
 	c.Assert(response, DeepEquals, expectedResponse)
 }
 
@@ -758,10 +805,14 @@ func (s *GoesTestSuite) TestSearch(c *C) {
 		},
 	}
 	response, _ := conn.Search(query, []string{indexName}, []string{docType}, url.Values{})
+	for i := range response.Hits.Hits {
+		response.Hits.Hits[i].RawSource = nil
+	}
 
 	expectedHits := Hits{
-		Total:    1,
-		MaxScore: 1.0,
+		Total:         1,
+		TotalRelation: "eq",
+		MaxScore:      1.0,
 		Hits: []Hit{
 			{
 				Index:  indexName,
@@ -827,7 +878,7 @@ func (s *GoesTestSuite) TestIndexStatus(c *C) {
 	conn := NewClient(ESHost, ESPort)
 
 	// _status endpoint was removed in ES 2.0
-	if version, _ := conn.Version(); version > "2" {
+	if version, _ := conn.ServerVersion(); version.AtLeast(2, 0) {
 		return
 	}
 
@@ -960,8 +1011,8 @@ func (s *GoesTestSuite) TestScroll(c *C) {
 	c.Assert(err, IsNil)
 
 	var query map[string]interface{}
-	version, _ := conn.Version()
-	if version > "5" {
+	version, _ := conn.ServerVersion()
+	if version.AtLeast(5, 0) {
 		query = map[string]interface{}{
 			"query": map[string]interface{}{
 				"bool": map[string]interface{}{
@@ -992,7 +1043,7 @@ func (s *GoesTestSuite) TestScroll(c *C) {
 	c.Assert(len(searchResults.ScrollID) > 0, Equals, true)
 
 	// Versions < 5.x don't include results in the initial response
-	if version < "5" {
+	if !version.Supports(ScanSortsByDoc) {
 		searchResults, err = conn.Scroll(searchResults.ScrollID, "1m")
 		c.Assert(err, IsNil)
 	}
@@ -1248,7 +1299,7 @@ func (s *GoesTestSuite) TestUpdate(c *C) {
 
 	// Now that we have an ordinary document indexed, try updating it
 	var query map[string]interface{}
-	if version, _ := conn.Version(); version > "5" {
+	if version, _ := conn.ServerVersion(); version.AtLeast(5, 0) {
 		query = map[string]interface{}{
 			"script": map[string]interface{}{
 				"inline": "ctx._source.counter += params.count",
@@ -1383,7 +1434,7 @@ func (s *GoesTestSuite) TestDeleteMapping(c *C) {
 	time.Sleep(200 * time.Millisecond)
 
 	response, err = conn.DeleteMapping("tweet", []string{indexName})
-	if version, _ := conn.Version(); version > "2" {
+	if version, _ := conn.ServerVersion(); !version.Supports(SupportsDeleteMapping) {
 		c.Assert(err, ErrorMatches, ".*not supported.*")
 		return
 	}
@@ -1440,6 +1491,7 @@ func (s *GoesTestSuite) TestAddAlias(c *C) {
 	}
 
 	response.Raw = nil
+	response.RawSource = nil
 	c.Assert(response, DeepEquals, expectedResponse)
 }
 
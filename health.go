@@ -0,0 +1,72 @@
+package goes
+
+import (
+	"context"
+	"net"
+	"time"
+)
+
+// StartHealthChecker pings the dead nodes of the cluster in a background
+// goroutine, waking up every interval, until Close is called. A dead node is
+// put back into the rotation as soon as it answers; otherwise the delay before
+// its next check is doubled. A node not answering within interval is
+// considered dead.
+func (c *Client) StartHealthChecker(interval time.Duration) {
+	pool := c.nodes()
+	if pool == nil {
+		return
+	}
+
+	stop := c.stopChan()
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case now := <-ticker.C:
+				for _, n := range pool.due(now) {
+					ctx, cancel := context.WithTimeout(context.Background(), interval)
+					c.checkNode(ctx, n)
+					cancel()
+				}
+			case <-stop:
+				return
+			}
+		}
+	}()
+}
+
+// checkNode sends a HEAD / request bound to ctx to a node and updates its state
+func (c *Client) checkNode(ctx context.Context, n *node) bool {
+	r := Request{Method: "HEAD"}
+	req, err := r.Request()
+	if err != nil {
+		return false
+	}
+	req = req.WithContext(ctx)
+	c.replaceHost(req, n)
+	if err := c.authenticate(req); err != nil {
+		return false
+	}
+
+	_, statusCode, err := c.doRequest(req)
+	if isTransportError(err) || statusCode >= 500 {
+		c.nodes().markDead(n)
+		return false
+	}
+
+	c.nodes().markAlive(n)
+	return true
+}
+
+// NodeStates returns the current state of every node known to the client.
+// A client created with NewClient only knows about its Host and Port, which
+// are always reported as alive.
+func (c *Client) NodeStates() []NodeState {
+	pool := c.nodes()
+	if pool == nil {
+		return []NodeState{{URL: "http://" + net.JoinHostPort(c.Host, c.Port), Alive: true}}
+	}
+	return pool.states()
+}
@@ -0,0 +1,84 @@
+package goes
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"time"
+
+	. "github.com/go-check/check"
+)
+
+func (s *GoesTestSuite) TestMarkDeadBackoff(c *C) {
+	conn := NewClusterClient([]string{"127.0.0.1:1"})
+	n := conn.pool.nodes[0]
+
+	conn.pool.markDead(n)
+	c.Assert(n.failures, Equals, 1)
+	c.Assert(n.checkAt.Sub(n.deadSince), Equals, deadNodeBackoff)
+
+	deadSince := n.deadSince
+	conn.pool.markDead(n)
+	c.Assert(n.failures, Equals, 2)
+	c.Assert(n.deadSince, Equals, deadSince)
+	c.Assert(n.checkAt.After(deadSince.Add(2*deadNodeBackoff-time.Millisecond)), Equals, true)
+
+	n.failures = 100
+	conn.pool.markDead(n)
+	c.Assert(n.checkAt.Before(time.Now().Add(deadNodeMaxBackoff+time.Second)), Equals, true)
+}
+
+func (s *GoesTestSuite) TestHealthChecker(c *C) {
+	var down = true
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		c.Check(r.Method, Equals, "HEAD")
+		if down {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer ts.Close()
+
+	conn := NewClusterClient([]string{strings.TrimPrefix(ts.URL, "http://")})
+	n := conn.pool.nodes[0]
+
+	conn.pool.markDead(n)
+	c.Assert(conn.checkNode(context.Background(), n), Equals, false)
+	c.Assert(conn.NodeStates()[0].Alive, Equals, false)
+	c.Assert(conn.NodeStates()[0].Failures, Equals, 2)
+
+	down = false
+	conn.pool.Lock()
+	n.checkAt = time.Now()
+	conn.pool.Unlock()
+
+	conn.StartHealthChecker(10 * time.Millisecond)
+	defer conn.Close()
+
+	for i := 0; i < 100 && !conn.NodeStates()[0].Alive; i++ {
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	c.Assert(conn.NodeStates(), DeepEquals, []NodeState{{URL: ts.URL, Alive: true}})
+}
+
+func (s *GoesTestSuite) TestHealthCheckTimeout(c *C) {
+	release := make(chan struct{})
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	}))
+	defer ts.Close()
+	defer close(release)
+
+	conn := NewClusterClient([]string{strings.TrimPrefix(ts.URL, "http://")})
+	n := conn.pool.nodes[0]
+	conn.pool.markDead(n)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	c.Assert(conn.checkNode(ctx, n), Equals, false)
+	c.Assert(time.Since(start) < time.Second, Equals, true)
+	c.Assert(conn.NodeStates()[0].Failures, Equals, 2)
+}
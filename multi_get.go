@@ -0,0 +1,131 @@
+package goes
+
+import (
+	"context"
+	"net/url"
+)
+
+// MultiGetEntry holds a document to get with a _mget request
+type MultiGetEntry struct {
+	Index string
+	// Ignored since 7.0, types being removed
+	Type string
+	ID   string
+
+	Routing string
+
+	// The _source filter of the document: false, a list of fields or an
+	// object with includes and excludes. The whole source is returned when nil.
+	Source interface{}
+}
+
+// MultiGetResult holds a document returned by a _mget request
+type MultiGetResult struct {
+	// The document, its Found field being false when it does not exist
+	Response *Response
+
+	// The SearchError of the document, e.g. when its index does not exist,
+	// nil otherwise
+	Err error
+}
+
+// MultiGet gets several documents in a single _mget request. A result is
+// returned for every document, in the same order. A failed document does
+// not make the others fail: its error is set in its result, the returned
+// error being the one of the whole request.
+func (c *Client) MultiGet(docs []MultiGetEntry, extraArgs url.Values) ([]MultiGetResult, error) {
+	return c.MultiGetContext(context.Background(), docs, extraArgs)
+}
+
+// MultiGetContext is the same as MultiGet, but the request is bound to ctx
+func (c *Client) MultiGetContext(ctx context.Context, docs []MultiGetEntry, extraArgs url.Values) ([]MultiGetResult, error) {
+	var version ServerVersion
+	for _, doc := range docs {
+		if doc.Type != "" || doc.Routing != "" {
+			version = c.layoutVersion(ctx)
+			break
+		}
+	}
+
+	body := make([]map[string]interface{}, len(docs))
+	for i, doc := range docs {
+		body[i] = multiGetDocument(doc, version)
+	}
+
+	r := Request{
+		Query:     map[string]interface{}{"docs": body},
+		Method:    "POST",
+		API:       "_mget",
+		ExtraArgs: extraArgs,
+	}
+	return c.multiGet(ctx, &r, len(docs))
+}
+
+// MultiGetIDs gets the documents of index with the given ids in a single
+// _mget request, as MultiGet does. documentType may be empty, and is ignored
+// since 7.0.
+func (c *Client) MultiGetIDs(index string, documentType string, ids []string, extraArgs url.Values) ([]MultiGetResult, error) {
+	return c.MultiGetIDsContext(context.Background(), index, documentType, ids, extraArgs)
+}
+
+// MultiGetIDsContext is the same as MultiGetIDs, but the request is bound to ctx
+func (c *Client) MultiGetIDsContext(ctx context.Context, index string, documentType string, ids []string, extraArgs url.Values) ([]MultiGetResult, error) {
+	r := Request{
+		Query:     map[string]interface{}{"ids": ids},
+		IndexList: []string{index},
+		Method:    "POST",
+		API:       "_mget",
+		ExtraArgs: extraArgs,
+	}
+
+	if documentType != "" && !c.typeless(ctx) {
+		r.TypeList = []string{documentType}
+	}
+
+	return c.multiGet(ctx, &r, len(ids))
+}
+
+// multiGet sends the _mget request r for count documents and returns their results
+func (c *Client) multiGet(ctx context.Context, r Requester, count int) ([]MultiGetResult, error) {
+	resp, err := c.DoContext(ctx, r)
+	if err != nil {
+		return nil, err
+	}
+	if len(resp.Docs) != count {
+		return nil, &SearchError{"Unexpected number of documents returned by _mget", resp.Status}
+	}
+
+	results := make([]MultiGetResult, count)
+	for i, doc := range resp.Docs {
+		if doc == nil {
+			doc = &Response{}
+		}
+		results[i] = MultiGetResult{Response: doc, Err: doc.searchError()}
+	}
+	return results, nil
+}
+
+// multiGetDocument returns the description of doc in a _mget request to a
+// server running version
+func multiGetDocument(doc MultiGetEntry, version ServerVersion) map[string]interface{} {
+	fields := map[string]interface{}{
+		"_index": doc.Index,
+		"_id":    doc.ID,
+	}
+	if doc.Type != "" && !version.Supports(Typeless) {
+		fields["_type"] = doc.Type
+	}
+
+	if doc.Routing != "" {
+		if version.Supports(UnprefixedMetadata) {
+			fields["routing"] = doc.Routing
+		} else {
+			fields["_routing"] = doc.Routing
+		}
+	}
+	if doc.Source != nil {
+		fields["_source"] = doc.Source
+	}
+
+	return fields
+}
@@ -0,0 +1,89 @@
+package goes
+
+import (
+	"net/http/httptest"
+
+	. "github.com/go-check/check"
+)
+
+func (s *GoesTestSuite) TestMultiGet(c *C) {
+	server := &requestRecorder{
+		version: "5.6.16",
+		response: `{"docs":[
+			{"_index":"a","_type":"t","_id":"1","_version":2,"found":true,"_source":{"user":"foo","id":9007199254740993}},
+			{"_index":"a","_type":"t","_id":"2","found":false},
+			{"_index":"b","_type":"t","_id":"3","error":{"type":"index_not_found_exception","reason":"no such index"}}
+		]}`,
+	}
+	ts := httptest.NewServer(server)
+	defer ts.Close()
+
+	conn, err := NewClientFromURL(ts.URL)
+	c.Assert(err, IsNil)
+
+	results, err := conn.MultiGet([]MultiGetEntry{
+		{Index: "a", Type: "t", ID: "1", Source: []string{"user", "id"}},
+		{Index: "a", Type: "t", ID: "2", Routing: "foo"},
+		{Index: "b", ID: "3", Source: false},
+	}, nil)
+	c.Assert(err, IsNil)
+	c.Assert(server.requests, DeepEquals, []string{`POST /_mget {"docs":[` +
+		`{"_id":"1","_index":"a","_source":["user","id"],"_type":"t"},` +
+		`{"_id":"2","_index":"a","_routing":"foo","_type":"t"},` +
+		`{"_id":"3","_index":"b","_source":false}]}`})
+
+	c.Assert(results, HasLen, 3)
+	c.Assert(results[0].Err, IsNil)
+	c.Assert(results[0].Response.Found, Equals, true)
+	c.Assert(results[0].Response.Version, Equals, 2)
+	var doc struct {
+		User string
+		ID   int64
+	}
+	c.Assert(results[0].Response.Decode(&doc), IsNil)
+	c.Assert(doc.ID, Equals, int64(9007199254740993))
+
+	c.Assert(results[1].Err, IsNil)
+	c.Assert(results[1].Response.Found, Equals, false)
+	c.Assert(results[1].Response.ID, Equals, "2")
+
+	c.Assert(results[2].Err, ErrorMatches, `.*index_not_found_exception.*`)
+	c.Assert(results[2].Response.Found, Equals, false)
+
+	server.requests = nil
+	server.response = `{"docs":[{"_index":"a","_type":"t","_id":"1","found":false},{"_index":"a","_type":"t","_id":"2","error":"[a] missing"}]}`
+	results, err = conn.MultiGetIDs("a", "t", []string{"1", "2"}, nil)
+	c.Assert(err, IsNil)
+	c.Assert(server.requests, DeepEquals, []string{`POST /a/t/_mget {"ids":["1","2"]}`})
+	c.Assert(results[0].Response.Found, Equals, false)
+	c.Assert(results[1].Err, ErrorMatches, `\[0\] \[a\] missing`)
+}
+
+func (s *GoesTestSuite) TestMultiGetTypeless(c *C) {
+	server := &requestRecorder{
+		version:  "7.10.2",
+		response: `{"docs":[{"_index":"a","_type":"_doc","_id":"1","found":true,"_source":{}},{"_index":"a","_type":"_doc","_id":"2","found":true,"_source":{}}]}`,
+	}
+	ts := httptest.NewServer(server)
+	defer ts.Close()
+
+	conn, err := NewClientFromURL(ts.URL)
+	c.Assert(err, IsNil)
+
+	_, err = conn.MultiGet([]MultiGetEntry{
+		{Index: "a", Type: "t", ID: "1", Routing: "foo"},
+		{Index: "a", ID: "2"},
+	}, nil)
+	c.Assert(err, IsNil)
+
+	_, err = conn.MultiGetIDs("a", "t", []string{"1", "2"}, nil)
+	c.Assert(err, IsNil)
+
+	c.Assert(server.requests, DeepEquals, []string{
+		`POST /_mget {"docs":[{"_id":"1","_index":"a","routing":"foo"},{"_id":"2","_index":"a"}]}`,
+		`POST /a/_mget {"ids":["1","2"]}`,
+	})
+
+	_, err = conn.MultiGetIDs("a", "", []string{"1"}, nil)
+	c.Assert(err, ErrorMatches, `\[200\] Unexpected number of documents returned by _mget`)
+}
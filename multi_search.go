@@ -0,0 +1,107 @@
+package goes
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/url"
+	"strings"
+)
+
+// MultiSearchEntry holds a search of a _msearch request
+type MultiSearchEntry struct {
+	IndexList []string
+	// Ignored by servers without types, since 7.0
+	TypeList []string
+
+	// A search query, an empty query matching all documents when nil
+	Query interface{}
+
+	// Arguments of the search such as routing, preference or search_type,
+	// sent in its header line
+	ExtraArgs url.Values
+}
+
+// MultiSearchResult holds the result of a search of a _msearch request
+type MultiSearchResult struct {
+	Response *Response
+
+	// The SearchError of the search, nil when it succeeded
+	Err error
+}
+
+// MultiSearch runs several searches in a single _msearch request. A result
+// is returned for every search, in the same order. A failed search does not
+// make the others fail: its error is set in its result, the returned error
+// being the one of the whole request.
+func (c *Client) MultiSearch(searches []MultiSearchEntry, extraArgs url.Values) ([]MultiSearchResult, error) {
+	return c.MultiSearchContext(context.Background(), searches, extraArgs)
+}
+
+// MultiSearchContext is the same as MultiSearch, but the request is bound to ctx
+func (c *Client) MultiSearchContext(ctx context.Context, searches []MultiSearchEntry, extraArgs url.Values) ([]MultiSearchResult, error) {
+	entries := searches
+	for i, search := range searches {
+		if len(search.TypeList) == 0 {
+			continue
+		}
+		if c.typeless(ctx) {
+			// The types are removed from a copy of the searches
+			entries = make([]MultiSearchEntry, len(searches))
+			copy(entries, searches)
+			for j := range entries[i:] {
+				entries[i+j].TypeList = nil
+			}
+		}
+		break
+	}
+
+	resp, err := c.DoContext(ctx, &MultiSearchRequest{Searches: entries, ExtraArgs: extraArgs})
+	if err != nil {
+		return nil, err
+	}
+	if len(resp.Responses) != len(searches) {
+		return nil, &SearchError{"Unexpected number of responses to _msearch", resp.Status}
+	}
+
+	results := make([]MultiSearchResult, len(searches))
+	for i, r := range resp.Responses {
+		if r == nil {
+			r = &Response{}
+		}
+		results[i] = MultiSearchResult{Response: r, Err: r.searchError()}
+	}
+	return results, nil
+}
+
+// encodeMultiSearchEntry appends the header and body lines of search in a
+// _msearch request to buf. Nothing is appended when an error is returned.
+func encodeMultiSearchEntry(buf *bytes.Buffer, search MultiSearchEntry) error {
+	header := map[string]interface{}{}
+	if len(search.IndexList) > 0 {
+		header["index"] = search.IndexList
+	}
+	if len(search.TypeList) > 0 {
+		header["type"] = search.TypeList
+	}
+	for name, values := range search.ExtraArgs {
+		header[name] = strings.Join(values, ",")
+	}
+
+	var query interface{} = map[string]interface{}{}
+	if search.Query != nil {
+		query = search.Query
+	}
+
+	start := buf.Len()
+	for _, line := range []interface{}{header, query} {
+		b, err := json.Marshal(line)
+		if err != nil {
+			buf.Truncate(start)
+			return err
+		}
+		buf.Write(b)
+		buf.WriteByte('\n')
+	}
+	return nil
+}
@@ -0,0 +1,81 @@
+package goes
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+
+	. "github.com/go-check/check"
+)
+
+func (s *GoesTestSuite) TestMultiSearch(c *C) {
+	var body, contentType, query string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := ioutil.ReadAll(r.Body)
+		body, contentType, query = string(b), r.Header.Get("Content-Type"), r.URL.RawQuery
+		c.Check(r.URL.Path, Equals, "/_msearch")
+		w.Write([]byte(`{"responses":[
+			{"took":2,"hits":{"total":1,"hits":[{"_index":"a","_type":"t","_id":"1","_source":{"user":"foo"}}]},"status":200},
+			{"error":{"root_cause":[],"type":"index_not_found_exception","reason":"no such index"},"status":404},
+			{"error":"IndexMissingException[[c] missing]"}
+		]}`))
+	}))
+	defer ts.Close()
+
+	conn, err := NewClientFromURL(ts.URL)
+	c.Assert(err, IsNil)
+	conn.WithVersion(ServerVersion{Major: 6, Minor: 8})
+
+	results, err := conn.MultiSearch([]MultiSearchEntry{
+		{
+			IndexList: []string{"a"},
+			TypeList:  []string{"t"},
+			Query:     map[string]interface{}{"query": map[string]interface{}{"term": map[string]interface{}{"user": "foo"}}},
+			ExtraArgs: url.Values{"routing": {"foo"}},
+		},
+		{IndexList: []string{"b", "c"}},
+		{IndexList: []string{"c"}, Query: map[string]interface{}{"size": 0}},
+	}, url.Values{"max_concurrent_searches": {"2"}})
+	c.Assert(err, IsNil)
+
+	c.Assert(body, Equals, `{"index":["a"],"routing":"foo","type":["t"]}
+{"query":{"term":{"user":"foo"}}}
+{"index":["b","c"]}
+{}
+{"index":["c"]}
+{"size":0}
+`)
+	c.Assert(contentType, Equals, "application/x-ndjson")
+	c.Assert(query, Equals, "max_concurrent_searches=2")
+
+	c.Assert(results, HasLen, 3)
+	c.Assert(results[0].Err, IsNil)
+	c.Assert(results[0].Response.Took, Equals, uint64(2))
+	c.Assert(results[0].Response.Hits.Hits, HasLen, 1)
+	c.Assert(results[0].Response.Hits.Hits[0].Source, DeepEquals, map[string]interface{}{"user": "foo"})
+
+	c.Assert(results[1].Err, ErrorMatches, `\[404\] .*index_not_found_exception.*`)
+	c.Assert(results[1].Response.Status, Equals, uint64(404))
+	c.Assert(results[2].Err, ErrorMatches, `\[0\] IndexMissingException\[\[c\] missing\]`)
+	c.Assert(results[2].Response.Error, Equals, "IndexMissingException[[c] missing]")
+}
+
+func (s *GoesTestSuite) TestMultiSearchErrors(c *C) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ioutil.ReadAll(r.Body)
+		w.Write([]byte(`{"responses":[{"hits":{"total":0,"hits":[]}}]}`))
+	}))
+	defer ts.Close()
+
+	conn, err := NewClientFromURL(ts.URL)
+	c.Assert(err, IsNil)
+	conn.WithVersion(ServerVersion{Major: 6, Minor: 8})
+
+	_, err = conn.MultiSearch([]MultiSearchEntry{{Query: map[string]interface{}{"size": make(chan int)}}}, nil)
+	c.Assert(err, ErrorMatches, "json: unsupported type: chan int")
+
+	// The responses are matched with the searches by position
+	_, err = conn.MultiSearch([]MultiSearchEntry{{}, {}}, nil)
+	c.Assert(err, ErrorMatches, `\[200\] Unexpected number of responses to _msearch`)
+}
@@ -0,0 +1,170 @@
+package goes
+
+import (
+	"net/url"
+	"sync"
+	"time"
+)
+
+const (
+	// deadNodeBackoff is the delay before a dead node is checked for the first time
+	deadNodeBackoff = time.Second
+	// deadNodeMaxBackoff caps the delay between two checks of a dead node
+	deadNodeMaxBackoff = 5 * time.Minute
+)
+
+// node is a single elasticsearch endpoint requests can be sent to
+type node struct {
+	url *url.URL
+
+	// dead is set when a request to the node failed at the transport level
+	dead      bool
+	deadSince time.Time
+
+	// failures counts the consecutive failed requests and health checks, and
+	// checkAt is when a dead node should be checked again
+	failures int
+	checkAt  time.Time
+}
+
+// nodePool holds the nodes of a cluster and hands them out in round-robin order
+type nodePool struct {
+	sync.Mutex
+
+	nodes []*node
+	next  int
+}
+
+func newNodePool(urls []*url.URL) *nodePool {
+	p := &nodePool{nodes: make([]*node, 0, len(urls))}
+	for _, u := range urls {
+		p.nodes = append(p.nodes, &node{url: u})
+	}
+	return p
+}
+
+// len returns the number of nodes in the pool, dead or alive
+func (p *nodePool) len() int {
+	p.Lock()
+	defer p.Unlock()
+	return len(p.nodes)
+}
+
+// pick returns the next live node in round-robin order. When every node is
+// dead, the one which has been dead for the longest time is returned so that
+// requests still get a chance to go through.
+func (p *nodePool) pick() *node {
+	p.Lock()
+	defer p.Unlock()
+
+	if len(p.nodes) == 0 {
+		return nil
+	}
+
+	for i := 0; i < len(p.nodes); i++ {
+		n := p.nodes[p.next%len(p.nodes)]
+		p.next = (p.next + 1) % len(p.nodes)
+		if !n.dead {
+			return n
+		}
+	}
+
+	oldest := p.nodes[0]
+	for _, n := range p.nodes[1:] {
+		if n.deadSince.Before(oldest.deadSince) {
+			oldest = n
+		}
+	}
+	return oldest
+}
+
+// markDead takes a node out of the rotation. The delay before it gets checked
+// again doubles with every consecutive failure.
+func (p *nodePool) markDead(n *node) {
+	p.Lock()
+	defer p.Unlock()
+
+	now := time.Now()
+	if !n.dead {
+		n.dead = true
+		n.deadSince = now
+	}
+
+	backoff := deadNodeMaxBackoff
+	if n.failures < 20 {
+		backoff = deadNodeBackoff << uint(n.failures)
+		if backoff > deadNodeMaxBackoff {
+			backoff = deadNodeMaxBackoff
+		}
+	}
+	n.failures++
+	n.checkAt = now.Add(backoff)
+}
+
+// markAlive puts a node back into the rotation
+func (p *nodePool) markAlive(n *node) {
+	p.Lock()
+	defer p.Unlock()
+
+	n.dead = false
+	n.deadSince = time.Time{}
+	n.failures = 0
+	n.checkAt = time.Time{}
+}
+
+// due returns the dead nodes which should be checked at time now
+func (p *nodePool) due(now time.Time) []*node {
+	p.Lock()
+	defer p.Unlock()
+
+	nodes := []*node{}
+	for _, n := range p.nodes {
+		if n.dead && !now.Before(n.checkAt) {
+			nodes = append(nodes, n)
+		}
+	}
+	return nodes
+}
+
+// states returns a snapshot of the state of every node
+func (p *nodePool) states() []NodeState {
+	p.Lock()
+	defer p.Unlock()
+
+	states := make([]NodeState, 0, len(p.nodes))
+	for _, n := range p.nodes {
+		states = append(states, NodeState{
+			URL:       n.url.Redacted(),
+			Alive:     !n.dead,
+			Failures:  n.failures,
+			DeadSince: n.deadSince,
+		})
+	}
+	return states
+}
+
+// replace swaps the nodes of the pool for the given ones. Nodes which were
+// already known keep their current state.
+func (p *nodePool) replace(urls []*url.URL) {
+	p.Lock()
+	defer p.Unlock()
+
+	known := make(map[string]*node, len(p.nodes))
+	for _, n := range p.nodes {
+		known[n.url.String()] = n
+	}
+
+	nodes := make([]*node, 0, len(urls))
+	for _, u := range urls {
+		if n, ok := known[u.String()]; ok {
+			nodes = append(nodes, n)
+			continue
+		}
+		nodes = append(nodes, &node{url: u})
+	}
+
+	p.nodes = nodes
+	if len(nodes) > 0 {
+		p.next %= len(nodes)
+	}
+}
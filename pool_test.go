@@ -0,0 +1,110 @@
+package goes
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+
+	. "github.com/go-check/check"
+)
+
+// newCountingServer starts a test server answering every request with an empty
+// JSON object and counting the requests it received
+func newCountingServer(count *int) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*count++
+		w.Write([]byte("{}"))
+	}))
+}
+
+func (s *GoesTestSuite) TestClusterClientRoundRobin(c *C) {
+	var first, second int
+	ts1 := newCountingServer(&first)
+	defer ts1.Close()
+	ts2 := newCountingServer(&second)
+	defer ts2.Close()
+
+	conn := NewClusterClient([]string{
+		strings.TrimPrefix(ts1.URL, "http://"),
+		strings.TrimPrefix(ts2.URL, "http://"),
+	})
+
+	for i := 0; i < 4; i++ {
+		_, err := conn.Do(&Request{Method: "GET"})
+		c.Assert(err, IsNil)
+	}
+
+	c.Assert(first, Equals, 2)
+	c.Assert(second, Equals, 2)
+}
+
+func (s *GoesTestSuite) TestClusterClientFailover(c *C) {
+	var count int
+	ts := newCountingServer(&count)
+	defer ts.Close()
+
+	down := httptest.NewServer(http.NotFoundHandler())
+	downAddress := strings.TrimPrefix(down.URL, "http://")
+	down.Close()
+
+	conn := NewClusterClient([]string{downAddress, strings.TrimPrefix(ts.URL, "http://")})
+
+	for i := 0; i < 3; i++ {
+		_, err := conn.Do(&Request{Method: "GET"})
+		c.Assert(err, IsNil)
+	}
+
+	c.Assert(count, Equals, 3)
+	c.Assert(conn.pool.nodes[0].dead, Equals, true)
+	c.Assert(conn.pool.nodes[1].dead, Equals, false)
+}
+
+func (s *GoesTestSuite) TestClusterClientAllNodesDown(c *C) {
+	down := httptest.NewServer(http.NotFoundHandler())
+	downAddress := strings.TrimPrefix(down.URL, "http://")
+	down.Close()
+
+	conn := NewClusterClient([]string{downAddress, downAddress})
+
+	_, err := conn.Do(&Request{Method: "GET"})
+	c.Assert(err, ErrorMatches, ".*connection refused")
+}
+
+func (s *GoesTestSuite) TestClusterClientCancelled(c *C) {
+	var count int
+	ts := newCountingServer(&count)
+	defer ts.Close()
+
+	conn := NewClusterClient([]string{strings.TrimPrefix(ts.URL, "http://")})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := conn.DoContext(ctx, &Request{Method: "GET"})
+	c.Assert(err, ErrorMatches, ".*context canceled")
+	c.Assert(count, Equals, 0)
+	c.Assert(conn.pool.nodes[0].dead, Equals, false)
+}
+
+func (s *GoesTestSuite) TestClusterClientAddresses(c *C) {
+	var count int
+	ts := newCountingServer(&count)
+	defer ts.Close()
+
+	conn := NewClusterClient([]string{ts.URL, strings.TrimPrefix(ts.URL, "http://")})
+	for i := 0; i < 2; i++ {
+		_, err := conn.Do(&Request{Method: "GET"})
+		c.Assert(err, IsNil)
+	}
+	c.Assert(count, Equals, 2)
+
+	invalid := [][]string{nil, {}, {""}, {"ftp://host:21"}, {ts.URL, "http://"}}
+	for _, addresses := range invalid {
+		conn := NewClusterClient(addresses)
+		resp, err := conn.Do(&Request{Method: "GET"})
+		c.Assert(err, NotNil, Commentf("%q", addresses))
+		c.Assert(resp, NotNil)
+	}
+	c.Assert(count, Equals, 2)
+}
@@ -0,0 +1,212 @@
+package query
+
+import (
+	"encoding/json"
+)
+
+// Aggregation is an aggregation of a search, with its sub-aggregations
+//
+// The setters of an aggregation only apply to the kinds of aggregations
+// supporting the matching parameter, Set can be used for the others:
+//
+//	NewSearch(MatchAll()).Size(0).
+//		Aggregation("users", TermsAggregation("user").Size(5).
+//			Aggregation("age", AvgAggregation("age")))
+type Aggregation struct {
+	kind   string
+	params params
+	aggs   map[string]*Aggregation
+
+	// the body of a filter aggregation, in place of params
+	filter Query
+}
+
+// NewAggregation returns an aggregation of the given kind, e.g. "geo_bounds",
+// for the ones with no constructor of their own
+func NewAggregation(kind string) *Aggregation {
+	return &Aggregation{kind: kind, params: params{}}
+}
+
+// field returns an aggregation of the given kind over field
+func field(kind string, name string) *Aggregation {
+	return NewAggregation(kind).Set("field", name)
+}
+
+// TermsAggregation returns a bucket aggregation with a bucket per value of field
+func TermsAggregation(name string) *Aggregation {
+	return field("terms", name)
+}
+
+// DateHistogramAggregation returns a bucket aggregation with a bucket per date
+// interval of field. The interval must be set with CalendarInterval or
+// FixedInterval since 7.2, with Interval before.
+func DateHistogramAggregation(name string) *Aggregation {
+	return field("date_histogram", name)
+}
+
+// HistogramAggregation returns a bucket aggregation with a bucket per
+// interval of the values of field
+func HistogramAggregation(name string, interval float64) *Aggregation {
+	return field("histogram", name).Set("interval", interval)
+}
+
+// FilterAggregation returns a single bucket aggregation of the documents matching filter
+func FilterAggregation(filter Query) *Aggregation {
+	agg := NewAggregation("filter")
+	agg.filter = filter
+	return agg
+}
+
+// NestedAggregation returns a single bucket aggregation of the nested objects at path
+func NestedAggregation(path string) *Aggregation {
+	return NewAggregation("nested").Set("path", path)
+}
+
+// AvgAggregation returns a metric aggregation averaging the values of field
+func AvgAggregation(name string) *Aggregation {
+	return field("avg", name)
+}
+
+// SumAggregation returns a metric aggregation summing the values of field
+func SumAggregation(name string) *Aggregation {
+	return field("sum", name)
+}
+
+// MinAggregation returns a metric aggregation of the lowest value of field
+func MinAggregation(name string) *Aggregation {
+	return field("min", name)
+}
+
+// MaxAggregation returns a metric aggregation of the highest value of field
+func MaxAggregation(name string) *Aggregation {
+	return field("max", name)
+}
+
+// CardinalityAggregation returns a metric aggregation counting the distinct
+// values of field, approximately
+func CardinalityAggregation(name string) *Aggregation {
+	return field("cardinality", name)
+}
+
+// ValueCountAggregation returns a metric aggregation counting the values of field
+func ValueCountAggregation(name string) *Aggregation {
+	return field("value_count", name)
+}
+
+// StatsAggregation returns a metric aggregation of the count, min, max, avg
+// and sum of the values of field
+func StatsAggregation(name string) *Aggregation {
+	return field("stats", name)
+}
+
+// PercentilesAggregation returns a metric aggregation of the given
+// percentiles of the values of field, or of the default ones when no percent
+// is given
+func PercentilesAggregation(name string, percents ...float64) *Aggregation {
+	agg := field("percentiles", name)
+	if len(percents) > 0 {
+		agg.Set("percents", percents)
+	}
+	return agg
+}
+
+// TopHitsAggregation returns a metric aggregation of the size most relevant
+// hits of a bucket
+func TopHitsAggregation(size int) *Aggregation {
+	return NewAggregation("top_hits").Size(size)
+}
+
+// PipelineAggregation returns a pipeline aggregation of the given kind, e.g.
+// "derivative" or "avg_bucket", over the aggregation at bucketsPath
+func PipelineAggregation(kind string, bucketsPath string) *Aggregation {
+	return NewAggregation(kind).Set("buckets_path", bucketsPath)
+}
+
+// Set sets a parameter of the aggregation
+func (a *Aggregation) Set(name string, value interface{}) *Aggregation {
+	a.params[name] = value
+	return a
+}
+
+// Aggregation adds a sub-aggregation, computed for each bucket
+func (a *Aggregation) Aggregation(name string, agg *Aggregation) *Aggregation {
+	if a.aggs == nil {
+		a.aggs = map[string]*Aggregation{}
+	}
+	a.aggs[name] = agg
+	return a
+}
+
+// Size sets the number of buckets of a terms aggregation, or of hits of a
+// top_hits aggregation
+func (a *Aggregation) Size(size int) *Aggregation {
+	return a.Set("size", size)
+}
+
+// Order sets the order of the buckets by key, e.g. "_count" or the name of a
+// sub-aggregation, and direction, "asc" or "desc"
+func (a *Aggregation) Order(key string, direction string) *Aggregation {
+	return a.Set("order", map[string]string{key: direction})
+}
+
+// MinDocCount sets the minimum number of documents of the returned buckets
+func (a *Aggregation) MinDocCount(count int) *Aggregation {
+	return a.Set("min_doc_count", count)
+}
+
+// Interval sets the interval of a date_histogram aggregation before 7.2, e.g. "day"
+func (a *Aggregation) Interval(interval string) *Aggregation {
+	return a.Set("interval", interval)
+}
+
+// CalendarInterval sets the calendar aware interval of a date_histogram
+// aggregation since 7.2, e.g. "1M"
+func (a *Aggregation) CalendarInterval(interval string) *Aggregation {
+	return a.Set("calendar_interval", interval)
+}
+
+// FixedInterval sets the fixed interval of a date_histogram aggregation
+// since 7.2, e.g. "90m"
+func (a *Aggregation) FixedInterval(interval string) *Aggregation {
+	return a.Set("fixed_interval", interval)
+}
+
+// Format sets the format of the keys or values, e.g. "yyyy-MM-dd"
+func (a *Aggregation) Format(format string) *Aggregation {
+	return a.Set("format", format)
+}
+
+// Missing sets the value used for the documents with no value
+func (a *Aggregation) Missing(value interface{}) *Aggregation {
+	return a.Set("missing", value)
+}
+
+// Sort adds a field to sort the hits of a top_hits aggregation by, in the
+// given order ("asc" or "desc")
+func (a *Aggregation) Sort(field string, order string) *Aggregation {
+	sort, _ := a.params["sort"].([]interface{})
+	return a.Set("sort", append(sort, map[string]string{field: order}))
+}
+
+// Source sets the fields of _source to return with the hits of a top_hits
+// aggregation, no source being returned when no field is given
+func (a *Aggregation) Source(fields ...string) *Aggregation {
+	if len(fields) == 0 {
+		return a.Set("_source", false)
+	}
+	return a.Set("_source", fields)
+}
+
+// MarshalJSON encodes the aggregation as {kind: {...}, "aggs": {...}}
+func (a *Aggregation) MarshalJSON() ([]byte, error) {
+	body := map[string]interface{}{}
+	if a.filter != nil {
+		body[a.kind] = a.filter
+	} else {
+		body[a.kind] = a.params
+	}
+	if len(a.aggs) > 0 {
+		body["aggs"] = a.aggs
+	}
+	return json.Marshal(body)
+}
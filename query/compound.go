@@ -0,0 +1,248 @@
+package query
+
+import (
+	"encoding/json"
+)
+
+// BoolQuery matches the documents matching a boolean combination of queries
+type BoolQuery struct {
+	must    []Query
+	filter  []Query
+	should  []Query
+	mustNot []Query
+	params  params
+}
+
+// Bool returns an empty bool query, which matches all documents
+func Bool() *BoolQuery {
+	return &BoolQuery{params: params{}}
+}
+
+// Must adds queries the documents must match, contributing to the score
+func (q *BoolQuery) Must(queries ...Query) *BoolQuery {
+	q.must = append(q.must, queries...)
+	return q
+}
+
+// Filter adds queries the documents must match, without scoring
+func (q *BoolQuery) Filter(queries ...Query) *BoolQuery {
+	q.filter = append(q.filter, queries...)
+	return q
+}
+
+// Should adds queries the documents should match
+func (q *BoolQuery) Should(queries ...Query) *BoolQuery {
+	q.should = append(q.should, queries...)
+	return q
+}
+
+// MustNot adds queries the documents must not match
+func (q *BoolQuery) MustNot(queries ...Query) *BoolQuery {
+	q.mustNot = append(q.mustNot, queries...)
+	return q
+}
+
+// MinimumShouldMatch sets the number or percentage of should clauses the
+// documents must match, e.g. 1 or "75%"
+func (q *BoolQuery) MinimumShouldMatch(minimum interface{}) *BoolQuery {
+	q.params["minimum_should_match"] = minimum
+	return q
+}
+
+// Boost sets the boost of the query, 1.0 by default
+func (q *BoolQuery) Boost(boost float64) *BoolQuery {
+	q.params["boost"] = boost
+	return q
+}
+
+// MarshalJSON encodes the query as {"bool": {...}}
+func (q *BoolQuery) MarshalJSON() ([]byte, error) {
+	body := params{}
+	for name, value := range q.params {
+		body[name] = value
+	}
+	for name, queries := range map[string][]Query{
+		"must":     q.must,
+		"filter":   q.filter,
+		"should":   q.should,
+		"must_not": q.mustNot,
+	} {
+		if len(queries) > 0 {
+			body[name] = queries
+		}
+	}
+	return clause("bool", body)
+}
+
+// ConstantScoreQuery matches the documents matching a filter, all with the same score
+type ConstantScoreQuery struct {
+	params params
+}
+
+// ConstantScore returns a query matching the documents matching filter
+func ConstantScore(filter Query) *ConstantScoreQuery {
+	return &ConstantScoreQuery{params: params{"filter": filter}}
+}
+
+// Boost sets the score of the matching documents, 1.0 by default
+func (q *ConstantScoreQuery) Boost(boost float64) *ConstantScoreQuery {
+	q.params["boost"] = boost
+	return q
+}
+
+// MarshalJSON encodes the query as {"constant_score": {...}}
+func (q *ConstantScoreQuery) MarshalJSON() ([]byte, error) {
+	return clause("constant_score", q.params)
+}
+
+// DisMaxQuery matches the documents matching any of its queries, scored by
+// the best matching one
+type DisMaxQuery struct {
+	params params
+}
+
+// DisMax returns a query matching the documents matching any of queries
+func DisMax(queries ...Query) *DisMaxQuery {
+	return &DisMaxQuery{params: params{"queries": queries}}
+}
+
+// TieBreaker sets the factor applied to the scores of the other matching queries
+func (q *DisMaxQuery) TieBreaker(tieBreaker float64) *DisMaxQuery {
+	q.params["tie_breaker"] = tieBreaker
+	return q
+}
+
+// MarshalJSON encodes the query as {"dis_max": {...}}
+func (q *DisMaxQuery) MarshalJSON() ([]byte, error) {
+	return clause("dis_max", q.params)
+}
+
+// FunctionScoreQuery modifies the score of the documents matching a query
+type FunctionScoreQuery struct {
+	functions []*ScoreFunction
+	params    params
+}
+
+// FunctionScore returns a query changing the score of the documents matching
+// query, all documents when query is nil
+func FunctionScore(query Query) *FunctionScoreQuery {
+	q := &FunctionScoreQuery{params: params{}}
+	if query != nil {
+		q.params["query"] = query
+	}
+	return q
+}
+
+// Add adds functions computing the score of the documents
+func (q *FunctionScoreQuery) Add(functions ...*ScoreFunction) *FunctionScoreQuery {
+	q.functions = append(q.functions, functions...)
+	return q
+}
+
+// ScoreMode sets how the scores of the functions are combined, e.g. "sum"
+func (q *FunctionScoreQuery) ScoreMode(mode string) *FunctionScoreQuery {
+	q.params["score_mode"] = mode
+	return q
+}
+
+// BoostMode sets how the score of the functions is combined with the score
+// of the query, e.g. "multiply"
+func (q *FunctionScoreQuery) BoostMode(mode string) *FunctionScoreQuery {
+	q.params["boost_mode"] = mode
+	return q
+}
+
+// MaxBoost sets the maximum score of the functions
+func (q *FunctionScoreQuery) MaxBoost(maxBoost float64) *FunctionScoreQuery {
+	q.params["max_boost"] = maxBoost
+	return q
+}
+
+// MinScore sets the minimum score of the documents to match
+func (q *FunctionScoreQuery) MinScore(minScore float64) *FunctionScoreQuery {
+	q.params["min_score"] = minScore
+	return q
+}
+
+// Boost sets the boost of the query, 1.0 by default
+func (q *FunctionScoreQuery) Boost(boost float64) *FunctionScoreQuery {
+	q.params["boost"] = boost
+	return q
+}
+
+// MarshalJSON encodes the query as {"function_score": {...}}
+func (q *FunctionScoreQuery) MarshalJSON() ([]byte, error) {
+	body := params{}
+	for name, value := range q.params {
+		body[name] = value
+	}
+	if len(q.functions) > 0 {
+		body["functions"] = q.functions
+	}
+	return clause("function_score", body)
+}
+
+// ScoreFunction computes the score of documents in a function_score query
+type ScoreFunction struct {
+	params params
+}
+
+// Weight returns a function scoring documents with weight
+func Weight(weight float64) *ScoreFunction {
+	return &ScoreFunction{params: params{"weight": weight}}
+}
+
+// FieldValueFactor returns a function scoring documents with the value of
+// field multiplied by factor, with modifier applied to it, e.g. "log1p".
+// No modifier is applied when it is empty.
+func FieldValueFactor(field string, factor float64, modifier string) *ScoreFunction {
+	p := params{"field": field, "factor": factor}
+	if modifier != "" {
+		p["modifier"] = modifier
+	}
+	return &ScoreFunction{params: params{"field_value_factor": p}}
+}
+
+// RandomScore returns a function scoring documents randomly. The scores are
+// reproducible for a given seed and field, e.g. "_seq_no". A different score is
+// given on each request when seed is nil.
+func RandomScore(seed interface{}, field string) *ScoreFunction {
+	p := params{}
+	if seed != nil {
+		p["seed"] = seed
+		p["field"] = field
+	}
+	return &ScoreFunction{params: params{"random_score": p}}
+}
+
+// Decay returns a function scoring documents by the distance of the value of
+// field to origin. function is one of "gauss", "linear" or "exp" and scale the
+// distance at which the score is halved.
+func Decay(function string, field string, origin interface{}, scale interface{}) *ScoreFunction {
+	return &ScoreFunction{params: params{
+		function: map[string]params{field: {"origin": origin, "scale": scale}},
+	}}
+}
+
+// ScriptScore returns a function scoring documents with script, e.g.
+// map[string]interface{}{"source": "Math.log(2 + doc['likes'].value)"}
+func ScriptScore(script interface{}) *ScoreFunction {
+	return &ScoreFunction{params: params{"script_score": params{"script": script}}}
+}
+
+// Filter restricts the function to the documents matching filter
+func (f *ScoreFunction) Filter(filter Query) *ScoreFunction {
+	f.params["filter"] = filter
+	return f
+}
+
+// Weight multiplies the score of the function by weight
+func (f *ScoreFunction) Weight(weight float64) *ScoreFunction {
+	f.params["weight"] = weight
+	return f
+}
+
+// MarshalJSON encodes the function with its filter and weight
+func (f *ScoreFunction) MarshalJSON() ([]byte, error) {
+	return json.Marshal(f.params)
+}
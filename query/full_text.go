@@ -0,0 +1,184 @@
+package query
+
+// MatchQuery matches the documents whose field matches an analyzed text
+type MatchQuery struct {
+	field  string
+	params params
+}
+
+// Match returns a query matching the documents whose field matches text
+func Match(field string, text interface{}) *MatchQuery {
+	return &MatchQuery{field: field, params: params{"query": text}}
+}
+
+// Operator sets how the terms of the text are combined, "or" by default
+func (q *MatchQuery) Operator(operator string) *MatchQuery {
+	q.params["operator"] = operator
+	return q
+}
+
+// Fuzziness sets the edit distance allowed when matching terms, e.g. "AUTO"
+func (q *MatchQuery) Fuzziness(fuzziness interface{}) *MatchQuery {
+	q.params["fuzziness"] = fuzziness
+	return q
+}
+
+// Analyzer sets the analyzer of the text, the one of field by default
+func (q *MatchQuery) Analyzer(analyzer string) *MatchQuery {
+	q.params["analyzer"] = analyzer
+	return q
+}
+
+// MinimumShouldMatch sets the number or percentage of terms the documents
+// must match, e.g. 2 or "75%"
+func (q *MatchQuery) MinimumShouldMatch(minimum interface{}) *MatchQuery {
+	q.params["minimum_should_match"] = minimum
+	return q
+}
+
+// Boost sets the boost of the query, 1.0 by default
+func (q *MatchQuery) Boost(boost float64) *MatchQuery {
+	q.params["boost"] = boost
+	return q
+}
+
+// MarshalJSON encodes the query as {"match": {field: {...}}}
+func (q *MatchQuery) MarshalJSON() ([]byte, error) {
+	return clause("match", params{q.field: q.params})
+}
+
+// MatchPhraseQuery matches the documents whose field holds a phrase
+type MatchPhraseQuery struct {
+	kind   string
+	field  string
+	params params
+}
+
+// MatchPhrase returns a query matching the documents whose field holds phrase
+func MatchPhrase(field string, phrase string) *MatchPhraseQuery {
+	return &MatchPhraseQuery{kind: "match_phrase", field: field, params: params{"query": phrase}}
+}
+
+// MatchPhrasePrefix returns a query matching the documents whose field holds
+// phrase, its last term being a prefix
+func MatchPhrasePrefix(field string, phrase string) *MatchPhraseQuery {
+	return &MatchPhraseQuery{kind: "match_phrase_prefix", field: field, params: params{"query": phrase}}
+}
+
+// Slop sets the number of positions the terms of the phrase may be moved by
+func (q *MatchPhraseQuery) Slop(slop int) *MatchPhraseQuery {
+	q.params["slop"] = slop
+	return q
+}
+
+// Analyzer sets the analyzer of the phrase, the one of field by default
+func (q *MatchPhraseQuery) Analyzer(analyzer string) *MatchPhraseQuery {
+	q.params["analyzer"] = analyzer
+	return q
+}
+
+// Boost sets the boost of the query, 1.0 by default
+func (q *MatchPhraseQuery) Boost(boost float64) *MatchPhraseQuery {
+	q.params["boost"] = boost
+	return q
+}
+
+// MarshalJSON encodes the query as {"match_phrase": {field: {...}}}, or
+// match_phrase_prefix
+func (q *MatchPhraseQuery) MarshalJSON() ([]byte, error) {
+	return clause(q.kind, params{q.field: q.params})
+}
+
+// MultiMatchQuery matches the documents whose fields match an analyzed text
+type MultiMatchQuery struct {
+	params params
+}
+
+// MultiMatch returns a query matching the documents whose fields match text.
+// The fields may be boosted, e.g. "title^3".
+func MultiMatch(text interface{}, fields ...string) *MultiMatchQuery {
+	return &MultiMatchQuery{params: params{"query": text, "fields": fields}}
+}
+
+// Type sets how the fields are matched and scored, "best_fields" by default
+func (q *MultiMatchQuery) Type(matchType string) *MultiMatchQuery {
+	q.params["type"] = matchType
+	return q
+}
+
+// Operator sets how the terms of the text are combined, "or" by default
+func (q *MultiMatchQuery) Operator(operator string) *MultiMatchQuery {
+	q.params["operator"] = operator
+	return q
+}
+
+// TieBreaker sets the factor applied to the scores of the fields other than
+// the best matching one
+func (q *MultiMatchQuery) TieBreaker(tieBreaker float64) *MultiMatchQuery {
+	q.params["tie_breaker"] = tieBreaker
+	return q
+}
+
+// Fuzziness sets the edit distance allowed when matching terms, e.g. "AUTO"
+func (q *MultiMatchQuery) Fuzziness(fuzziness interface{}) *MultiMatchQuery {
+	q.params["fuzziness"] = fuzziness
+	return q
+}
+
+// MinimumShouldMatch sets the number or percentage of terms the documents
+// must match, e.g. 2 or "75%"
+func (q *MultiMatchQuery) MinimumShouldMatch(minimum interface{}) *MultiMatchQuery {
+	q.params["minimum_should_match"] = minimum
+	return q
+}
+
+// Boost sets the boost of the query, 1.0 by default
+func (q *MultiMatchQuery) Boost(boost float64) *MultiMatchQuery {
+	q.params["boost"] = boost
+	return q
+}
+
+// MarshalJSON encodes the query as {"multi_match": {...}}
+func (q *MultiMatchQuery) MarshalJSON() ([]byte, error) {
+	return clause("multi_match", q.params)
+}
+
+// QueryStringQuery matches the documents matching a query in the Lucene syntax
+type QueryStringQuery struct {
+	params params
+}
+
+// QueryString returns a query matching the documents matching query, e.g.
+// "user:foo AND message:bar"
+func QueryString(query string) *QueryStringQuery {
+	return &QueryStringQuery{params: params{"query": query}}
+}
+
+// DefaultField sets the field searched when none is given in the query
+func (q *QueryStringQuery) DefaultField(field string) *QueryStringQuery {
+	q.params["default_field"] = field
+	return q
+}
+
+// Fields sets the fields searched when none is given in the query
+func (q *QueryStringQuery) Fields(fields ...string) *QueryStringQuery {
+	q.params["fields"] = fields
+	return q
+}
+
+// DefaultOperator sets how the terms of the query are combined, "or" by default
+func (q *QueryStringQuery) DefaultOperator(operator string) *QueryStringQuery {
+	q.params["default_operator"] = operator
+	return q
+}
+
+// Boost sets the boost of the query, 1.0 by default
+func (q *QueryStringQuery) Boost(boost float64) *QueryStringQuery {
+	q.params["boost"] = boost
+	return q
+}
+
+// MarshalJSON encodes the query as {"query_string": {...}}
+func (q *QueryStringQuery) MarshalJSON() ([]byte, error) {
+	return clause("query_string", q.params)
+}
@@ -0,0 +1,39 @@
+package query
+
+// NestedQuery matches the documents whose nested objects match a query
+type NestedQuery struct {
+	params params
+}
+
+// Nested returns a query matching the documents whose nested objects at path
+// match query
+func Nested(path string, query Query) *NestedQuery {
+	return &NestedQuery{params: params{"path": path, "query": query}}
+}
+
+// ScoreMode sets how the scores of the matching nested objects are combined,
+// "avg" by default
+func (q *NestedQuery) ScoreMode(mode string) *NestedQuery {
+	q.params["score_mode"] = mode
+	return q
+}
+
+// IgnoreUnmapped sets whether no document is matched instead of failing when
+// path is not mapped
+func (q *NestedQuery) IgnoreUnmapped(ignore bool) *NestedQuery {
+	q.params["ignore_unmapped"] = ignore
+	return q
+}
+
+// InnerHits returns the matching nested objects with each hit, with the
+// options of inner hits, e.g. map[string]interface{}{"size": 3}, or an empty
+// map for the default ones
+func (q *NestedQuery) InnerHits(options interface{}) *NestedQuery {
+	q.params["inner_hits"] = options
+	return q
+}
+
+// MarshalJSON encodes the query as {"nested": {...}}
+func (q *NestedQuery) MarshalJSON() ([]byte, error) {
+	return clause("nested", q.params)
+}
@@ -0,0 +1,156 @@
+// Package query provides builders for the query DSL of elasticsearch.
+//
+// The builders encode themselves as JSON, so that they can be passed wherever
+// the goes package expects a query:
+//
+//	q := query.Bool().
+//		Must(query.Match("message", "foo bar").Operator("and")).
+//		Filter(query.Range("date").Gte("now-1d"), query.Term("user", "foo"))
+//
+//	conn.Search(query.NewSearch(q).Size(10), []string{"index"}, nil, nil)
+package query
+
+import (
+	"encoding/json"
+)
+
+// Query is a query clause, as found under the "query" key of a search
+type Query interface {
+	json.Marshaler
+}
+
+// params holds the parameters of a query, only the ones set being encoded
+type params map[string]interface{}
+
+// clause encodes a query of the given kind, {kind: body}
+func clause(kind string, body interface{}) ([]byte, error) {
+	return json.Marshal(map[string]interface{}{kind: body})
+}
+
+// RawQuery is a query given as any value encoding to a valid query, for the
+// ones with no builder
+type RawQuery struct {
+	value interface{}
+}
+
+// Raw returns a query encoded as value, e.g. a map[string]interface{}
+func Raw(value interface{}) *RawQuery {
+	return &RawQuery{value: value}
+}
+
+// MarshalJSON encodes the value of the query
+func (q *RawQuery) MarshalJSON() ([]byte, error) {
+	return json.Marshal(q.value)
+}
+
+// MatchAllQuery matches all documents
+type MatchAllQuery struct {
+	params params
+}
+
+// MatchAll returns a query matching all documents
+func MatchAll() *MatchAllQuery {
+	return &MatchAllQuery{params: params{}}
+}
+
+// Boost sets the score of the matching documents, 1.0 by default
+func (q *MatchAllQuery) Boost(boost float64) *MatchAllQuery {
+	q.params["boost"] = boost
+	return q
+}
+
+// MarshalJSON encodes the query as {"match_all": {...}}
+func (q *MatchAllQuery) MarshalJSON() ([]byte, error) {
+	return clause("match_all", q.params)
+}
+
+// MatchNoneQuery matches no documents
+type MatchNoneQuery struct{}
+
+// MatchNone returns a query matching no documents
+func MatchNone() *MatchNoneQuery {
+	return &MatchNoneQuery{}
+}
+
+// MarshalJSON encodes the query as {"match_none": {}}
+func (q *MatchNoneQuery) MarshalJSON() ([]byte, error) {
+	return clause("match_none", params{})
+}
+
+// Search is the body of a search request made of a query and the parameters
+// of the search. It can be passed as the query of Client.Search, as well as
+// Client.Count or Client.DeleteByQuery when only the query is set.
+type Search struct {
+	query  Query
+	sort   []interface{}
+	aggs   map[string]*Aggregation
+	params params
+}
+
+// NewSearch returns the body of a search for q
+func NewSearch(q Query) *Search {
+	return &Search{query: q, params: params{}}
+}
+
+// From sets the offset of the first hit to return
+func (s *Search) From(from int) *Search {
+	s.params["from"] = from
+	return s
+}
+
+// Size sets the number of hits to return
+func (s *Search) Size(size int) *Search {
+	s.params["size"] = size
+	return s
+}
+
+// Sort adds a field to sort the hits by, in the given order ("asc" or "desc").
+// The hits are sorted by the fields in the order they were added.
+func (s *Search) Sort(field string, order string) *Search {
+	s.sort = append(s.sort, map[string]string{field: order})
+	return s
+}
+
+// Source sets the fields of _source to return with the hits, all of them by
+// default. No source is returned when no field is given.
+func (s *Search) Source(fields ...string) *Search {
+	if len(fields) == 0 {
+		s.params["_source"] = false
+		return s
+	}
+	s.params["_source"] = fields
+	return s
+}
+
+// Aggregation adds an aggregation of the hits of the search
+func (s *Search) Aggregation(name string, agg *Aggregation) *Search {
+	if s.aggs == nil {
+		s.aggs = map[string]*Aggregation{}
+	}
+	s.aggs[name] = agg
+	return s
+}
+
+// Set sets a parameter of the search which has no setter of its own
+func (s *Search) Set(name string, value interface{}) *Search {
+	s.params[name] = value
+	return s
+}
+
+// MarshalJSON encodes the body of the search
+func (s *Search) MarshalJSON() ([]byte, error) {
+	body := map[string]interface{}{}
+	for name, value := range s.params {
+		body[name] = value
+	}
+	if s.query != nil {
+		body["query"] = s.query
+	}
+	if len(s.sort) > 0 {
+		body["sort"] = s.sort
+	}
+	if len(s.aggs) > 0 {
+		body["aggs"] = s.aggs
+	}
+	return json.Marshal(body)
+}
@@ -0,0 +1,120 @@
+package query
+
+import (
+	"encoding/json"
+	"flag"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	. "github.com/go-check/check"
+)
+
+var update = flag.Bool("update", false, "update the golden files of testdata")
+
+// Hook up gocheck into the gotest runner.
+func Test(t *testing.T) { TestingT(t) }
+
+type QueryTestSuite struct{}
+
+var _ = Suite(&QueryTestSuite{})
+
+// golden lists queries along with the golden file, in testdata, holding their JSON
+var golden = []struct {
+	file  string
+	query interface{}
+}{
+	{"match_all.json", MatchAll()},
+	{"match_none.json", MatchNone()},
+	{"raw.json", Raw(map[string]interface{}{"geo_distance": map[string]interface{}{"distance": "10km", "location": "40,-70"}})},
+	{"bool.json", Bool().
+		Must(Match("message", "foo bar").Operator("and")).
+		Filter(Range("date").Gte("now-1d").Lt("now"), Term("user", "foo")).
+		Should(Prefix("tag", "go"), Wildcard("tag", "el*c")).
+		MustNot(Exists("deleted")).
+		MinimumShouldMatch(1).
+		Boost(2)},
+	{"bool_empty.json", Bool()},
+	{"match.json", Match("message", "foo bar").Fuzziness("AUTO").Analyzer("standard").MinimumShouldMatch("75%").Boost(1.5)},
+	{"match_phrase.json", MatchPhrase("message", "quick brown fox").Slop(2)},
+	{"match_phrase_prefix.json", MatchPhrasePrefix("message", "quick brown f").Analyzer("standard")},
+	{"multi_match.json", MultiMatch("foo bar", "title^3", "message").Type("cross_fields").Operator("and").TieBreaker(0.3)},
+	{"query_string.json", QueryString("user:foo AND message:bar").DefaultField("message").DefaultOperator("and")},
+	{"term.json", Term("user", "foo").Boost(2)},
+	{"terms.json", Terms("user", "foo", "bar", 42)},
+	{"terms_empty.json", Terms("user")},
+	{"range.json", Range("date").Gt("2020-01-01").Lte("2020-12-31").Format("yyyy-MM-dd").TimeZone("+01:00")},
+	{"exists.json", Exists("user")},
+	{"prefix.json", Prefix("user", "fo").Rewrite("constant_score")},
+	{"wildcard.json", Wildcard("user", "f?o*").Boost(0.5)},
+	{"regexp.json", Regexp("user", "fo+")},
+	{"ids.json", IDs("1", "2")},
+	{"nested.json", Nested("comments", Bool().Must(Match("comments.author", "foo"))).ScoreMode("max").IgnoreUnmapped(true).InnerHits(map[string]interface{}{})},
+	{"constant_score.json", ConstantScore(Term("user", "foo")).Boost(1.2)},
+	{"dis_max.json", DisMax(Match("title", "foo"), Match("message", "foo")).TieBreaker(0.7)},
+	{"function_score.json", FunctionScore(Match("message", "foo")).
+		Add(
+			Weight(2).Filter(Term("user", "foo")),
+			FieldValueFactor("likes", 1.2, "log1p"),
+			RandomScore(10, "_seq_no"),
+			Decay("gauss", "date", "now", "10d").Weight(3),
+			ScriptScore(map[string]interface{}{"source": "Math.log(2 + doc['likes'].value)"}),
+		).
+		ScoreMode("sum").BoostMode("multiply").MaxBoost(42).MinScore(1)},
+	{"function_score_all.json", FunctionScore(nil).Add(RandomScore(nil, ""))},
+	{"search.json", NewSearch(Match("message", "foo")).From(10).Size(5).Sort("date", "desc").Sort("_score", "desc").Source("user", "message")},
+	{"search_no_source.json", NewSearch(MatchAll()).Source().Set("track_total_hits", true)},
+	{"aggregations.json", NewSearch(MatchAll()).Size(0).
+		Aggregation("users", TermsAggregation("user").Size(5).Order("age", "desc").MinDocCount(2).
+			Aggregation("age", AvgAggregation("age").Missing(0)).
+			Aggregation("latest", TopHitsAggregation(1).Sort("date", "desc").Source("message"))).
+		Aggregation("per_day", DateHistogramAggregation("date").CalendarInterval("1d").Format("yyyy-MM-dd").
+			Aggregation("likes", SumAggregation("likes")).
+			Aggregation("likes_derivative", PipelineAggregation("derivative", "likes"))).
+		Aggregation("max_likes_per_day", PipelineAggregation("max_bucket", "per_day>likes"))},
+	{"aggregation_metrics.json", map[string]*Aggregation{
+		"min":         MinAggregation("age"),
+		"max":         MaxAggregation("age"),
+		"stats":       StatsAggregation("age"),
+		"users":       CardinalityAggregation("user"),
+		"messages":    ValueCountAggregation("message"),
+		"percentiles": PercentilesAggregation("load_time", 50, 95, 99.9),
+		"default":     PercentilesAggregation("load_time"),
+	}},
+	{"aggregation_buckets.json", map[string]*Aggregation{
+		"foo":       FilterAggregation(Term("user", "foo")).Aggregation("age", AvgAggregation("age")),
+		"comments":  NestedAggregation("comments").Aggregation("authors", TermsAggregation("comments.author")),
+		"ages":      HistogramAggregation("age", 10),
+		"old_style": DateHistogramAggregation("date").Interval("day"),
+		"bounds":    NewAggregation("geo_bounds").Set("field", "location"),
+	}},
+}
+
+func (s *QueryTestSuite) TestGolden(c *C) {
+	for _, g := range golden {
+		data, err := json.MarshalIndent(g.query, "", "  ")
+		c.Assert(err, IsNil)
+		data = append(data, '\n')
+
+		path := filepath.Join("testdata", g.file)
+		if *update {
+			c.Assert(ioutil.WriteFile(path, data, 0644), IsNil)
+			continue
+		}
+
+		expected, err := ioutil.ReadFile(path)
+		c.Assert(err, IsNil)
+		c.Assert(string(data), Equals, string(expected), Commentf("testdata/%s", g.file))
+	}
+}
+
+func (s *QueryTestSuite) TestNested(c *C) {
+	// Queries are encoded the same way whether they are given by pointer or
+	// as a Query
+	var q Query = Term("user", "foo")
+	direct, err := json.Marshal(q)
+	c.Assert(err, IsNil)
+	inBool, err := json.Marshal(Bool().Filter(q))
+	c.Assert(err, IsNil)
+	c.Assert(string(inBool), Equals, `{"bool":{"filter":[`+string(direct)+`]}}`)
+}
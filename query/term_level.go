@@ -0,0 +1,189 @@
+package query
+
+// TermQuery matches the documents whose field holds an exact term
+type TermQuery struct {
+	field  string
+	params params
+}
+
+// Term returns a query matching the documents whose field holds value, which
+// is not analyzed
+func Term(field string, value interface{}) *TermQuery {
+	return &TermQuery{field: field, params: params{"value": value}}
+}
+
+// Boost sets the boost of the query, 1.0 by default
+func (q *TermQuery) Boost(boost float64) *TermQuery {
+	q.params["boost"] = boost
+	return q
+}
+
+// MarshalJSON encodes the query as {"term": {field: {...}}}
+func (q *TermQuery) MarshalJSON() ([]byte, error) {
+	return clause("term", params{q.field: q.params})
+}
+
+// TermsQuery matches the documents whose field holds any of several exact terms
+type TermsQuery struct {
+	field  string
+	values []interface{}
+	params params
+}
+
+// Terms returns a query matching the documents whose field holds any of values
+func Terms(field string, values ...interface{}) *TermsQuery {
+	if values == nil {
+		values = []interface{}{}
+	}
+	return &TermsQuery{field: field, values: values, params: params{}}
+}
+
+// Boost sets the boost of the query, 1.0 by default
+func (q *TermsQuery) Boost(boost float64) *TermsQuery {
+	q.params["boost"] = boost
+	return q
+}
+
+// MarshalJSON encodes the query as {"terms": {field: [...]}}
+func (q *TermsQuery) MarshalJSON() ([]byte, error) {
+	body := params{q.field: q.values}
+	for name, value := range q.params {
+		body[name] = value
+	}
+	return clause("terms", body)
+}
+
+// RangeQuery matches the documents whose field is within a range
+type RangeQuery struct {
+	field  string
+	params params
+}
+
+// Range returns a query matching the documents whose field is within the
+// bounds set on the query. It has no bounds by default.
+func Range(field string) *RangeQuery {
+	return &RangeQuery{field: field, params: params{}}
+}
+
+// Gt sets the exclusive lower bound of the range
+func (q *RangeQuery) Gt(value interface{}) *RangeQuery {
+	q.params["gt"] = value
+	return q
+}
+
+// Gte sets the inclusive lower bound of the range
+func (q *RangeQuery) Gte(value interface{}) *RangeQuery {
+	q.params["gte"] = value
+	return q
+}
+
+// Lt sets the exclusive upper bound of the range
+func (q *RangeQuery) Lt(value interface{}) *RangeQuery {
+	q.params["lt"] = value
+	return q
+}
+
+// Lte sets the inclusive upper bound of the range
+func (q *RangeQuery) Lte(value interface{}) *RangeQuery {
+	q.params["lte"] = value
+	return q
+}
+
+// Format sets the date format of the bounds, e.g. "yyyy-MM-dd"
+func (q *RangeQuery) Format(format string) *RangeQuery {
+	q.params["format"] = format
+	return q
+}
+
+// TimeZone sets the time zone of the date bounds, e.g. "+01:00"
+func (q *RangeQuery) TimeZone(timeZone string) *RangeQuery {
+	q.params["time_zone"] = timeZone
+	return q
+}
+
+// Boost sets the boost of the query, 1.0 by default
+func (q *RangeQuery) Boost(boost float64) *RangeQuery {
+	q.params["boost"] = boost
+	return q
+}
+
+// MarshalJSON encodes the query as {"range": {field: {...}}}
+func (q *RangeQuery) MarshalJSON() ([]byte, error) {
+	return clause("range", params{q.field: q.params})
+}
+
+// ExistsQuery matches the documents having a value for a field
+type ExistsQuery struct {
+	field string
+}
+
+// Exists returns a query matching the documents having a value for field
+func Exists(field string) *ExistsQuery {
+	return &ExistsQuery{field: field}
+}
+
+// MarshalJSON encodes the query as {"exists": {"field": field}}
+func (q *ExistsQuery) MarshalJSON() ([]byte, error) {
+	return clause("exists", params{"field": q.field})
+}
+
+// PatternQuery matches the documents whose field holds a term matching a
+// pattern, be it a prefix, a wildcard or a regular expression
+type PatternQuery struct {
+	kind   string
+	field  string
+	params params
+}
+
+// Prefix returns a query matching the documents whose field holds a term
+// starting with prefix
+func Prefix(field string, prefix string) *PatternQuery {
+	return &PatternQuery{kind: "prefix", field: field, params: params{"value": prefix}}
+}
+
+// Wildcard returns a query matching the documents whose field holds a term
+// matching pattern, where "*" matches any characters and "?" any character
+func Wildcard(field string, pattern string) *PatternQuery {
+	return &PatternQuery{kind: "wildcard", field: field, params: params{"value": pattern}}
+}
+
+// Regexp returns a query matching the documents whose field holds a term
+// matching the regular expression pattern
+func Regexp(field string, pattern string) *PatternQuery {
+	return &PatternQuery{kind: "regexp", field: field, params: params{"value": pattern}}
+}
+
+// Rewrite sets how the query is rewritten, e.g. "constant_score"
+func (q *PatternQuery) Rewrite(rewrite string) *PatternQuery {
+	q.params["rewrite"] = rewrite
+	return q
+}
+
+// Boost sets the boost of the query, 1.0 by default
+func (q *PatternQuery) Boost(boost float64) *PatternQuery {
+	q.params["boost"] = boost
+	return q
+}
+
+// MarshalJSON encodes the query as {"prefix": {field: {...}}}, or wildcard or regexp
+func (q *PatternQuery) MarshalJSON() ([]byte, error) {
+	return clause(q.kind, params{q.field: q.params})
+}
+
+// IDsQuery matches the documents with the given ids
+type IDsQuery struct {
+	ids []string
+}
+
+// IDs returns a query matching the documents with the given ids
+func IDs(ids ...string) *IDsQuery {
+	if ids == nil {
+		ids = []string{}
+	}
+	return &IDsQuery{ids: ids}
+}
+
+// MarshalJSON encodes the query as {"ids": {"values": [...]}}
+func (q *IDsQuery) MarshalJSON() ([]byte, error) {
+	return clause("ids", params{"values": q.ids})
+}
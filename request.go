@@ -3,10 +3,12 @@ package goes
 import (
 	"bytes"
 	"encoding/json"
+	"io"
 	"io/ioutil"
 	"net/http"
 	"net/url"
 	"strings"
+	"sync"
 )
 
 // Requester implements Request which builds an HTTP request for Elasticsearch
@@ -43,6 +45,9 @@ type Request struct {
 
 	// Used for the id field when indexing a document
 	ID string
+
+	// Retry policy overriding the one of the client for this request only
+	RetryPolicy *RetryPolicy
 }
 
 // URL builds a URL for a Request
@@ -97,11 +102,149 @@ func (req *Request) Request() (*http.Request, error) {
 	newReq.URL = req.URL()
 	newReq.Body = ioutil.NopCloser(bytes.NewReader(postData))
 	newReq.ContentLength = int64(len(postData))
+	newReq.GetBody = func() (io.ReadCloser, error) {
+		return ioutil.NopCloser(bytes.NewReader(postData)), nil
+	}
 
-	if req.Method == "POST" || req.Method == "PUT" {
+	if req.Method == "POST" || req.Method == "PUT" || len(postData) > 0 {
 		newReq.Header.Set("Content-Type", "application/json")
 	}
 	return newReq, nil
 }
 
 var _ Requester = (*Request)(nil)
+
+// queryMap returns the fields of a query as a map, for parameters to be added
+// to it. The query may be of any type encoded as a JSON object.
+func queryMap(query interface{}) (map[string]interface{}, error) {
+	fields := map[string]interface{}{}
+	if query == nil {
+		return fields, nil
+	}
+
+	b, err := json.Marshal(query)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(b, &fields); err != nil {
+		return nil, err
+	}
+	return fields, nil
+}
+
+// bulkBuffers holds the buffers documents are encoded into by BulkRequest
+var bulkBuffers = sync.Pool{
+	New: func() interface{} {
+		return new(bytes.Buffer)
+	},
+}
+
+// BulkRequest is a _bulk request whose body is encoded from its documents
+// while it is sent, instead of being built in memory beforehand
+type BulkRequest struct {
+	Documents []Document
+
+	// Version of the server the request is sent to, as returned by
+	// Client.ServerVersion. It is only needed when some documents have a type
+	// or metadata.
+	Version ServerVersion
+
+	// A list of extra URL arguments
+	ExtraArgs url.Values
+}
+
+// bodyError wraps the errors happening while the body of a request is generated
+type bodyError struct {
+	err error
+}
+
+func (e *bodyError) Error() string {
+	return e.err.Error()
+}
+
+// Request generates an http.Request whose body streams the documents of the BulkRequest
+func (req *BulkRequest) Request() (*http.Request, error) {
+	return newNDJSONRequest("/_bulk", req.ExtraArgs, req.encode)
+}
+
+// newNDJSONRequest returns a POST request to path whose body is written by
+// encode while it is sent
+func newNDJSONRequest(path string, extraArgs url.Values, encode func(w io.Writer) error) (*http.Request, error) {
+	newReq, err := http.NewRequest("POST", "", nil)
+	if err != nil {
+		return nil, err
+	}
+	newReq.URL = &url.URL{
+		Path:     path,
+		RawQuery: extraArgs.Encode(),
+	}
+	newReq.Body = pipeBody(encode)
+	newReq.ContentLength = -1
+	newReq.GetBody = func() (io.ReadCloser, error) {
+		return pipeBody(encode), nil
+	}
+	newReq.Header.Set("Content-Type", "application/x-ndjson")
+
+	return newReq, nil
+}
+
+// pipeBody starts encode writing into a pipe and returns its reading end
+func pipeBody(encode func(w io.Writer) error) io.ReadCloser {
+	pr, pw := io.Pipe()
+	go func() {
+		pw.CloseWithError(encode(pw))
+	}()
+	return pr
+}
+
+// encode writes the lines of every document to w
+func (req *BulkRequest) encode(w io.Writer) error {
+	buf := bulkBuffers.Get().(*bytes.Buffer)
+	defer bulkBuffers.Put(buf)
+
+	for _, doc := range req.Documents {
+		buf.Reset()
+		if err := encodeBulkDocument(buf, doc, req.Version); err != nil {
+			return &bodyError{err}
+		}
+		if _, err := w.Write(buf.Bytes()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+var _ Requester = (*BulkRequest)(nil)
+
+// MultiSearchRequest is a _msearch request whose body is encoded from its
+// searches while it is sent
+type MultiSearchRequest struct {
+	Searches []MultiSearchEntry
+
+	// A list of extra URL arguments
+	ExtraArgs url.Values
+}
+
+// Request generates an http.Request whose body streams the searches of the MultiSearchRequest
+func (req *MultiSearchRequest) Request() (*http.Request, error) {
+	return newNDJSONRequest("/_msearch", req.ExtraArgs, req.encode)
+}
+
+// encode writes the lines of every search to w
+func (req *MultiSearchRequest) encode(w io.Writer) error {
+	buf := bulkBuffers.Get().(*bytes.Buffer)
+	defer bulkBuffers.Put(buf)
+
+	for _, search := range req.Searches {
+		buf.Reset()
+		if err := encodeMultiSearchEntry(buf, search); err != nil {
+			return &bodyError{err}
+		}
+		if _, err := w.Write(buf.Bytes()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+var _ Requester = (*MultiSearchRequest)(nil)
@@ -0,0 +1,44 @@
+package goes
+
+import (
+	"encoding/json"
+)
+
+// UnmarshalJSON decodes the hits of a search. Their total is a number up to
+// ES 6.x and an object with its relation since 7.0, e.g.
+// {"value": 10000, "relation": "gte"}; in both cases Total and TotalRelation
+// are set.
+func (h *Hits) UnmarshalJSON(data []byte) error {
+	type hits Hits
+	var fields struct {
+		*hits
+		// Shadows Total, which is decoded from it
+		Total json.RawMessage `json:"total"`
+	}
+	fields.hits = (*hits)(h)
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return err
+	}
+
+	h.Total, h.TotalRelation = 0, ""
+	switch {
+	case isNull(fields.Total):
+		// track_total_hits is false
+		return nil
+	case fields.Total[0] == '{':
+		var total struct {
+			Value    uint64
+			Relation string
+		}
+		if err := json.Unmarshal(fields.Total, &total); err != nil {
+			return err
+		}
+		h.Total, h.TotalRelation = total.Value, total.Relation
+	default:
+		if err := json.Unmarshal(fields.Total, &h.Total); err != nil {
+			return err
+		}
+		h.TotalRelation = "eq"
+	}
+	return nil
+}
@@ -0,0 +1,112 @@
+package goes
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+
+	. "github.com/go-check/check"
+)
+
+// responseFixtures lists the responses captured from each version of ES in
+// testdata, search-<version>.json being a search of twitter and
+// error-<version>.json a failed search
+var responseFixtures = []struct {
+	version  string
+	total    uint64
+	relation string
+	hitType  string
+
+	status    uint64
+	errorType string
+	reason    string
+	rootCause string
+}{
+	{"1.7", 2, "eq", "tweet", 404, "IndexMissingException", "[missing] missing", ""},
+	{"2.4", 2, "eq", "tweet", 404, "index_not_found_exception", "no such index", "index_not_found_exception"},
+	{"5.6", 2, "eq", "tweet", 404, "index_not_found_exception", "no such index", "index_not_found_exception"},
+	{"6.8", 2, "eq", "_doc", 400, "search_phase_execution_exception", "all shards failed", "query_shard_exception"},
+	{"7.10", 2, "eq", "_doc", 404, "index_not_found_exception", "no such index [missing]", "index_not_found_exception"},
+	{"8.11", 10000, "gte", "", 404, "index_not_found_exception", "no such index [missing]", "index_not_found_exception"},
+}
+
+func (s *GoesTestSuite) TestResponseFixtures(c *C) {
+	for _, t := range responseFixtures {
+		search, err := ioutil.ReadFile("testdata/search-" + t.version + ".json")
+		c.Assert(err, IsNil)
+		failure, err := ioutil.ReadFile("testdata/error-" + t.version + ".json")
+		c.Assert(err, IsNil)
+
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path == "/twitter/_search" {
+				w.Write(search)
+				return
+			}
+			w.WriteHeader(int(t.status))
+			w.Write(failure)
+		}))
+		conn, err := NewClientFromURL(ts.URL)
+		c.Assert(err, IsNil)
+		version, err := ParseServerVersion(t.version)
+		c.Assert(err, IsNil)
+		conn.WithVersion(version)
+		comment := Commentf("version %s", t.version)
+
+		response, err := conn.Search(nil, []string{"twitter"}, nil, nil)
+		c.Assert(err, IsNil, comment)
+		c.Assert(response.Hits.Total, Equals, t.total, comment)
+		c.Assert(response.Hits.TotalRelation, Equals, t.relation, comment)
+		c.Assert(response.Hits.MaxScore, Equals, 1.0, comment)
+		c.Assert(response.Hits.Hits, HasLen, 2, comment)
+		for _, hit := range response.Hits.Hits {
+			c.Assert(hit.Index, Equals, "twitter", comment)
+			c.Assert(hit.Type, Equals, t.hitType, comment)
+			c.Assert(hit.Source["user"], NotNil, comment)
+		}
+		c.Assert(response.Error, Equals, "", comment)
+		c.Assert(response.Cause, IsNil, comment)
+
+		response, err = conn.Search(nil, []string{"missing"}, nil, nil)
+		ts.Close()
+		c.Assert(err, NotNil, comment)
+		c.Assert(err.(*SearchError).StatusCode, Equals, t.status, comment)
+		c.Assert(response.Status, Equals, t.status, comment)
+		c.Assert(response.Error, Not(Equals), "", comment)
+		c.Assert(response.Cause, NotNil, comment)
+		c.Assert(response.Cause.Type, Equals, t.errorType, comment)
+		c.Assert(response.Cause.Reason, Equals, t.reason, comment)
+		if t.rootCause == "" {
+			c.Assert(response.Cause.RootCause, HasLen, 0, comment)
+		} else {
+			c.Assert(response.Cause.RootCause, HasLen, 1, comment)
+			c.Assert(response.Cause.RootCause[0].Type, Equals, t.rootCause, comment)
+		}
+	}
+}
+
+func (s *GoesTestSuite) TestHitsTotal(c *C) {
+	totals := []struct {
+		json     string
+		total    uint64
+		relation string
+	}{
+		{`{"total":3,"hits":[]}`, 3, "eq"},
+		{`{"total":{"value":3,"relation":"eq"},"hits":[]}`, 3, "eq"},
+		{`{"total":{"value":10000,"relation":"gte"},"hits":[]}`, 10000, "gte"},
+		// track_total_hits is false
+		{`{"hits":[]}`, 0, ""},
+		{`{"total":null,"hits":[]}`, 0, ""},
+	}
+	for _, t := range totals {
+		var hits Hits
+		c.Assert(json.Unmarshal([]byte(t.json), &hits), IsNil, Commentf(t.json))
+		c.Assert(hits.Total, Equals, t.total, Commentf(t.json))
+		c.Assert(hits.TotalRelation, Equals, t.relation, Commentf(t.json))
+		c.Assert(hits.Hits, HasLen, 0)
+	}
+
+	var hits Hits
+	c.Assert(json.Unmarshal([]byte(`{"total":"3"}`), &hits), NotNil)
+	c.Assert(json.Unmarshal([]byte(`{"total":{"value":"3"}}`), &hits), NotNil)
+}
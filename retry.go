@@ -0,0 +1,169 @@
+package goes
+
+import (
+	"context"
+	"math/rand"
+	"net/http"
+	"time"
+)
+
+// RetryPolicy describes how requests failing with a transport error or a
+// retryable status code are sent again
+type RetryPolicy struct {
+	// Maximum number of attempts, the first one included
+	MaxAttempts int
+
+	// Delay before the first retry, doubled for every following one
+	Backoff time.Duration
+
+	// Upper bound of the delay between two attempts, no bound when zero
+	MaxBackoff time.Duration
+
+	// Fraction (between 0 and 1) of each delay which is randomized, so that
+	// clients rejected at the same time do not all come back at the same time
+	Jitter float64
+
+	// HTTP status codes worth a retry
+	StatusCodes []uint64
+
+	// HTTP methods which may be retried, all of them when empty.
+	//
+	// A request failing with a transport error, such as a timeout, may still
+	// have been executed by the server. Retrying a POST request then risks
+	// executing it twice: documents indexed by Index without an id are created
+	// twice, as are the ones of a _bulk request. Set Methods to the idempotent
+	// methods, such as GET, HEAD, PUT and DELETE, to avoid it at the expense
+	// of searches, which are sent with POST.
+	Methods []string
+}
+
+// DefaultRetryPolicy retries rejected executions (429), unavailable nodes
+// (502, 503, 504) and transport errors up to 3 times. As its Methods are
+// empty, requests sent with POST are retried too, possibly creating
+// duplicate documents when a transport error happens after the server
+// indexed them.
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts: 3,
+	Backoff:     100 * time.Millisecond,
+	MaxBackoff:  10 * time.Second,
+	Jitter:      0.2,
+	StatusCodes: []uint64{429, 502, 503, 504},
+}
+
+// retryable reports whether a request sent with method and ending with
+// statusCode and err should be sent again
+func (p *RetryPolicy) retryable(method string, statusCode uint64, err error) bool {
+	if len(p.Methods) > 0 {
+		allowed := false
+		for _, m := range p.Methods {
+			if m == method {
+				allowed = true
+				break
+			}
+		}
+		if !allowed {
+			return false
+		}
+	}
+
+	if isTransportError(err) {
+		return true
+	}
+	return err == nil && p.retryableStatus(statusCode)
+}
+
+// retryableStatus reports whether statusCode is worth a retry
+func (p *RetryPolicy) retryableStatus(statusCode uint64) bool {
+	for _, code := range p.StatusCodes {
+		if code == statusCode {
+			return true
+		}
+	}
+	return false
+}
+
+// delay returns how long to wait after the given attempt (starting at 1) failed
+func (p *RetryPolicy) delay(attempt int) time.Duration {
+	d := p.Backoff
+	for i := 1; i < attempt && (p.MaxBackoff == 0 || d < p.MaxBackoff); i++ {
+		d *= 2
+	}
+	if p.MaxBackoff > 0 && d > p.MaxBackoff {
+		d = p.MaxBackoff
+	}
+
+	if p.Jitter > 0 {
+		d += time.Duration(p.Jitter * float64(d) * (2*rand.Float64() - 1))
+	}
+	return d
+}
+
+// retryPolicier is implemented by requests overriding the retry policy of the client
+type retryPolicier interface {
+	retryPolicy() *RetryPolicy
+}
+
+func (req *Request) retryPolicy() *RetryPolicy {
+	return req.RetryPolicy
+}
+
+// WithRetryPolicy sets the policy used to retry failed requests. Requests are
+// not retried when p is nil, which is the default. Returns the original client.
+func (c *Client) WithRetryPolicy(p *RetryPolicy) *Client {
+	c.retry = p
+	return c
+}
+
+type retryPolicyKey struct{}
+
+// ContextWithRetryPolicy returns a copy of ctx carrying a retry policy which
+// overrides the one of the client for the requests bound to it
+func ContextWithRetryPolicy(ctx context.Context, p *RetryPolicy) context.Context {
+	return context.WithValue(ctx, retryPolicyKey{}, p)
+}
+
+// retryPolicyFor returns the policy applying to r, if any. The policy of the
+// request comes first, then the one of the context and finally the one of the client.
+func (c *Client) retryPolicyFor(ctx context.Context, r Requester) *RetryPolicy {
+	if rp, ok := r.(retryPolicier); ok {
+		if p := rp.retryPolicy(); p != nil {
+			return p
+		}
+	}
+	if p, ok := ctx.Value(retryPolicyKey{}).(*RetryPolicy); ok && p != nil {
+		return p
+	}
+	return c.retry
+}
+
+// perform sends the request built by r and returns it along with the raw
+// response, retrying it according to the retry policy of the client until ctx
+// is done
+func (c *Client) perform(ctx context.Context, r Requester) (*http.Request, []byte, uint64, error) {
+	policy := c.retryPolicyFor(ctx, r)
+
+	for attempt := 1; ; attempt++ {
+		req, body, statusCode, err := c.performOnce(ctx, r)
+		if req == nil || policy == nil || attempt >= policy.MaxAttempts ||
+			ctx.Err() != nil || !policy.retryable(req.Method, statusCode, err) {
+			return req, body, statusCode, err
+		}
+
+		if err := sleepContext(ctx, policy.delay(attempt)); err != nil {
+			return req, nil, statusCode, err
+		}
+	}
+}
+
+// sleepContext waits for d, returning early with the error of ctx when it is done
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,128 @@
+package goes
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"time"
+
+	. "github.com/go-check/check"
+)
+
+// newRejectingServer starts a test server rejecting the first requests with a
+// 429 status code and checking that every attempt carries the same body
+func newRejectingServer(c *C, rejections int, count *int) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*count++
+		body, _ := ioutil.ReadAll(r.Body)
+		c.Check(string(body), Equals, `{"query":"foo"}`)
+
+		if *count <= rejections {
+			w.WriteHeader(http.StatusTooManyRequests)
+			w.Write([]byte(`{"error":"es_rejected_execution_exception","status":429}`))
+			return
+		}
+		w.Write([]byte(`{"took":1}`))
+	}))
+}
+
+func newRetryTestClient(ts *httptest.Server) *Client {
+	address := strings.Split(strings.TrimPrefix(ts.URL, "http://"), ":")
+	return NewClient(address[0], address[1])
+}
+
+func (s *GoesTestSuite) TestRetryPolicy(c *C) {
+	var count int
+	ts := newRejectingServer(c, 2, &count)
+	defer ts.Close()
+
+	policy := DefaultRetryPolicy
+	policy.Backoff = time.Millisecond
+	conn := newRetryTestClient(ts).WithRetryPolicy(&policy)
+
+	resp, err := conn.Search(map[string]interface{}{"query": "foo"}, []string{"i"}, nil, nil)
+	c.Assert(err, IsNil)
+	c.Assert(resp.Took, Equals, uint64(1))
+	c.Assert(count, Equals, 3)
+}
+
+func (s *GoesTestSuite) TestRetryPolicyGivesUp(c *C) {
+	var count int
+	ts := newRejectingServer(c, 5, &count)
+	defer ts.Close()
+
+	policy := DefaultRetryPolicy
+	policy.Backoff = time.Millisecond
+	conn := newRetryTestClient(ts).WithRetryPolicy(&policy)
+
+	_, err := conn.Search(map[string]interface{}{"query": "foo"}, []string{"i"}, nil, nil)
+	c.Assert(err, ErrorMatches, `\[429\] .*es_rejected_execution_exception.*`)
+	c.Assert(count, Equals, 3)
+}
+
+func (s *GoesTestSuite) TestRetryPolicyPerRequest(c *C) {
+	var count int
+	ts := newRejectingServer(c, 1, &count)
+	defer ts.Close()
+
+	policy := DefaultRetryPolicy
+	policy.Backoff = time.Millisecond
+	conn := newRetryTestClient(ts).WithRetryPolicy(&policy)
+
+	r := Request{
+		Query:       map[string]interface{}{"query": "foo"},
+		Method:      "POST",
+		API:         "_search",
+		RetryPolicy: &RetryPolicy{MaxAttempts: 1},
+	}
+	_, err := conn.Do(&r)
+	c.Assert(err, ErrorMatches, `\[429\] .*`)
+	c.Assert(count, Equals, 1)
+
+	r.RetryPolicy = &RetryPolicy{MaxAttempts: 2, StatusCodes: []uint64{429}, Methods: []string{"GET"}}
+	_, err = conn.Do(&r)
+	c.Assert(err, IsNil)
+	c.Assert(count, Equals, 2)
+}
+
+func (s *GoesTestSuite) TestRetryPolicyDelay(c *C) {
+	policy := RetryPolicy{Backoff: 100 * time.Millisecond, MaxBackoff: time.Second}
+
+	c.Assert(policy.delay(1), Equals, 100*time.Millisecond)
+	c.Assert(policy.delay(2), Equals, 200*time.Millisecond)
+	c.Assert(policy.delay(4), Equals, 800*time.Millisecond)
+	c.Assert(policy.delay(5), Equals, time.Second)
+	c.Assert(policy.delay(50), Equals, time.Second)
+
+	policy.Jitter = 0.5
+	for i := 0; i < 100; i++ {
+		d := policy.delay(1)
+		c.Assert(d >= 50*time.Millisecond && d <= 150*time.Millisecond, Equals, true)
+	}
+}
+
+func (s *GoesTestSuite) TestRetryPolicyContext(c *C) {
+	var count int
+	ts := newRejectingServer(c, 5, &count)
+	defer ts.Close()
+
+	conn := newRetryTestClient(ts)
+
+	policy := DefaultRetryPolicy
+	policy.Backoff = time.Millisecond
+	ctx := ContextWithRetryPolicy(context.Background(), &policy)
+
+	_, err := conn.SearchContext(ctx, map[string]interface{}{"query": "foo"}, []string{"i"}, nil, nil)
+	c.Assert(err, ErrorMatches, `\[429\] .*`)
+	c.Assert(count, Equals, 3)
+
+	policy.Backoff = time.Hour
+	ctx, cancel := context.WithTimeout(ctx, 50*time.Millisecond)
+	defer cancel()
+
+	_, err = conn.SearchContext(ctx, map[string]interface{}{"query": "foo"}, []string{"i"}, nil, nil)
+	c.Assert(err, Equals, context.DeadlineExceeded)
+	c.Assert(count, Equals, 4)
+}
@@ -0,0 +1,199 @@
+package goes
+
+import (
+	"context"
+	"time"
+)
+
+// ScrollIterator iterates over all the hits of a query using the scroll API
+//
+// It hides the differences between versions of elasticsearch, such as the
+// first response of a scan holding no hits before 5.x. The search context is
+// cleared once all the hits were read, or when Close is called.
+//
+//	it := conn.NewScrollIterator(query, []string{"index"}, nil, "1m", 100)
+//	defer it.Close()
+//	for it.Next() {
+//		hit := it.Hit()
+//		...
+//	}
+//	if err := it.Err(); err != nil {
+//		...
+//	}
+type ScrollIterator struct {
+	client *Client
+	ctx    context.Context
+
+	query     interface{}
+	indexList []string
+	typeList  []string
+	timeout   string
+	size      int
+
+	scrollID string
+	started  bool
+	done     bool
+	err      error
+
+	hits []Hit
+	// position of the current hit in hits
+	pos int
+}
+
+// NewScrollIterator returns a ScrollIterator over the hits of query, fetched
+// size at a time (per shard before 5.x). timeout is how long the search
+// context is kept between two requests, e.g. "1m". No request is sent until
+// Next is called.
+func (c *Client) NewScrollIterator(query interface{}, indexList []string, typeList []string, timeout string, size int) *ScrollIterator {
+	return c.NewScrollIteratorContext(context.Background(), query, indexList, typeList, timeout, size)
+}
+
+// NewScrollIteratorContext is the same as NewScrollIterator, but the requests are bound to ctx
+func (c *Client) NewScrollIteratorContext(ctx context.Context, query interface{}, indexList []string, typeList []string, timeout string, size int) *ScrollIterator {
+	return &ScrollIterator{
+		client:    c,
+		ctx:       ctx,
+		query:     query,
+		indexList: indexList,
+		typeList:  typeList,
+		timeout:   timeout,
+		size:      size,
+		pos:       -1,
+	}
+}
+
+// Next moves to the next hit, fetching more hits when needed. It returns
+// false once there are no more hits or an error happened.
+func (it *ScrollIterator) Next() bool {
+	if it.err != nil {
+		return false
+	}
+
+	it.pos++
+	for it.pos >= len(it.hits) {
+		if it.done {
+			return false
+		}
+		if err := it.fetch(); err != nil {
+			it.err = err
+			it.Close()
+			return false
+		}
+	}
+	return true
+}
+
+// fetch replaces the hits with the next ones
+func (it *ScrollIterator) fetch() error {
+	var (
+		resp *Response
+		err  error
+	)
+	if !it.started {
+		resp, err = it.client.ScanContext(it.ctx, it.query, it.indexList, it.typeList, it.timeout, it.size)
+	} else {
+		resp, err = it.client.ScrollContext(it.ctx, it.scrollID, it.timeout)
+	}
+	if err != nil {
+		return err
+	}
+	if resp.ScrollID != "" {
+		it.scrollID = resp.ScrollID
+	}
+
+	it.hits = resp.Hits.Hits
+	it.pos = 0
+
+	// The first response of a scan has no hits before 5.x, an empty response
+	// only means the end when it comes from a scroll or nothing matched
+	if len(it.hits) == 0 && (it.started || resp.Hits.Total == 0) {
+		it.done = true
+		return it.Close()
+	}
+	it.started = true
+	return nil
+}
+
+// Hit returns the current hit, nil before the first call to Next or once
+// there are no more hits
+func (it *ScrollIterator) Hit() *Hit {
+	if it.pos < 0 || it.pos >= len(it.hits) {
+		return nil
+	}
+	return &it.hits[it.pos]
+}
+
+// Hits returns the current hit along with the following ones received in the
+// same response, and moves to the last of them. It allows handling the hits
+// in batches.
+func (it *ScrollIterator) Hits() []Hit {
+	if it.pos < 0 || it.pos >= len(it.hits) {
+		return nil
+	}
+	hits := it.hits[it.pos:]
+	it.pos = len(it.hits) - 1
+	return hits
+}
+
+// Err returns the error which stopped the iteration, if any
+func (it *ScrollIterator) Err() error {
+	return it.err
+}
+
+// Close clears the search context of the scroll. It is called once all the
+// hits were read, but must be called when stopping before that.
+func (it *ScrollIterator) Close() error {
+	it.done = true
+	if it.scrollID == "" {
+		return nil
+	}
+
+	scrollID := it.scrollID
+	it.scrollID = ""
+	_, err := it.client.ClearScrollContext(detachedContext{it.ctx}, scrollID)
+	if searchErr, ok := err.(*SearchError); ok && searchErr.StatusCode == 404 {
+		// The search context already expired
+		return nil
+	}
+	return err
+}
+
+// Chan returns a channel receiving all the hits, which is closed once they
+// were all sent or an error happened, Err telling which. The iterator must
+// not be used otherwise afterwards. The context of the iterator must be
+// cancelled when not reading all the hits from the channel.
+func (it *ScrollIterator) Chan() <-chan Hit {
+	hits := make(chan Hit)
+	go func() {
+		defer close(hits)
+		defer it.Close()
+
+		for it.Next() {
+			select {
+			case hits <- *it.Hit():
+			case <-it.ctx.Done():
+				it.err = it.ctx.Err()
+				return
+			}
+		}
+	}()
+	return hits
+}
+
+// detachedContext holds the values of a context but not its cancellation, so
+// that the search contexts of a cancelled iteration are still freed
+type detachedContext struct {
+	context.Context
+}
+
+func (detachedContext) Deadline() (time.Time, bool) {
+	return time.Time{}, false
+}
+
+func (detachedContext) Done() <-chan struct{} {
+	return nil
+}
+
+func (detachedContext) Err() error {
+	return nil
+}
@@ -0,0 +1,134 @@
+package goes
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+
+	. "github.com/go-check/check"
+)
+
+var scrollVersions = []struct {
+	version string
+	cleared string
+}{
+	{"1.7.5", `scroll4`},
+	{"7.10.2", `{"scroll_id":["scroll4"]}`},
+}
+
+func (s *GoesTestSuite) TestScrollIterator(c *C) {
+	for _, t := range scrollVersions {
+		server := &searchServer{version: t.version, total: 5}
+		ts := httptest.NewServer(server)
+
+		conn, err := NewClientFromURL(ts.URL)
+		c.Assert(err, IsNil)
+
+		it := conn.NewScrollIterator(nil, []string{"i"}, nil, "1m", 2)
+		c.Assert(it.Hit(), IsNil)
+
+		ids := []string{}
+		for it.Next() {
+			ids = append(ids, it.Hit().ID)
+		}
+		c.Assert(it.Err(), IsNil)
+		c.Assert(it.Hit(), IsNil)
+		c.Assert(ids, DeepEquals, []string{"0", "1", "2", "3", "4"}, Commentf("version %s", t.version))
+		c.Assert(server.cleared, DeepEquals, []string{t.cleared}, Commentf("version %s", t.version))
+
+		c.Assert(it.Close(), IsNil)
+		c.Assert(server.cleared, HasLen, 1)
+		ts.Close()
+	}
+}
+
+func (s *GoesTestSuite) TestScrollIteratorHits(c *C) {
+	server := &searchServer{version: "7.10.2", total: 5}
+	ts := httptest.NewServer(server)
+	defer ts.Close()
+
+	conn, err := NewClientFromURL(ts.URL)
+	c.Assert(err, IsNil)
+
+	it := conn.NewScrollIterator(nil, []string{"i"}, nil, "1m", 2)
+	c.Assert(it.Next(), Equals, true)
+	c.Assert(it.Hit().ID, Equals, "0")
+	c.Assert(it.Next(), Equals, true)
+	c.Assert(it.Hits(), HasLen, 1)
+	c.Assert(it.Next(), Equals, true)
+	hits := it.Hits()
+	c.Assert(hits, HasLen, 2)
+	c.Assert(hits[0].ID, Equals, "2")
+	c.Assert(hits[1].Source, DeepEquals, map[string]interface{}{"n": 3.0})
+
+	c.Assert(it.Close(), IsNil)
+	c.Assert(it.Next(), Equals, false)
+	c.Assert(server.cleared, DeepEquals, []string{`{"scroll_id":["scroll2"]}`})
+}
+
+func (s *GoesTestSuite) TestScrollIteratorEmpty(c *C) {
+	for _, t := range scrollVersions {
+		server := &searchServer{version: t.version}
+		ts := httptest.NewServer(server)
+
+		conn, err := NewClientFromURL(ts.URL)
+		c.Assert(err, IsNil)
+
+		it := conn.NewScrollIterator(nil, []string{"i"}, nil, "1m", 2)
+		c.Assert(it.Next(), Equals, false)
+		c.Assert(it.Err(), IsNil)
+		c.Assert(server.cleared, HasLen, 1)
+		ts.Close()
+	}
+}
+
+func (s *GoesTestSuite) TestScrollIteratorError(c *C) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/" {
+			w.Write([]byte(`{"version":{"number":"7.10.2"}}`))
+			return
+		}
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"error":"no such index","status":404}`))
+	}))
+	defer ts.Close()
+
+	conn, err := NewClientFromURL(ts.URL)
+	c.Assert(err, IsNil)
+
+	it := conn.NewScrollIterator(nil, []string{"i"}, nil, "1m", 2)
+	c.Assert(it.Next(), Equals, false)
+	c.Assert(it.Err(), ErrorMatches, `\[404\] no such index`)
+	c.Assert(it.Next(), Equals, false)
+}
+
+func (s *GoesTestSuite) TestScrollIteratorChan(c *C) {
+	server := &searchServer{version: "7.10.2", total: 5}
+	ts := httptest.NewServer(server)
+	defer ts.Close()
+
+	conn, err := NewClientFromURL(ts.URL)
+	c.Assert(err, IsNil)
+
+	it := conn.NewScrollIterator(nil, []string{"i"}, nil, "1m", 2)
+	ids := []string{}
+	for hit := range it.Chan() {
+		ids = append(ids, hit.ID)
+	}
+	c.Assert(it.Err(), IsNil)
+	c.Assert(ids, DeepEquals, []string{"0", "1", "2", "3", "4"})
+
+	// Stopping early
+	ctx, cancel := context.WithCancel(context.Background())
+	it = conn.NewScrollIteratorContext(ctx, nil, []string{"i"}, nil, "1m", 2)
+	hits := it.Chan()
+	<-hits
+	cancel()
+	for range hits {
+	}
+	c.Assert(it.Err(), ErrorMatches, ".*context canceled")
+
+	server.Lock()
+	defer server.Unlock()
+	c.Assert(server.cleared, HasLen, 2)
+}
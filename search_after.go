@@ -0,0 +1,223 @@
+package goes
+
+import (
+	"context"
+	"encoding/json"
+)
+
+// SearchAfterIterator pages through the hits of a sorted search using
+// search_after, which is not limited to the first 10000 hits like from/size
+//
+// The sort must hold a tie breaker unique to each document, such as a copy of
+// its id, for no hit to be skipped, unless a point in time is used. When asked
+// for and supported by the server (7.10 and later), a point in time is opened
+// so that every page is read from the same view of the indices. It is closed
+// once all the hits were read, or when Close is called.
+//
+//	it := conn.NewSearchAfterIterator(query, []interface{}{map[string]string{"date": "asc"}}, []string{"index"}, nil, 100, "1m")
+//	defer it.Close()
+//	for it.Next() {
+//		hit := it.Hit()
+//		...
+//	}
+//	if err := it.Err(); err != nil {
+//		...
+//	}
+type SearchAfterIterator struct {
+	client *Client
+	ctx    context.Context
+
+	query     interface{}
+	sort      []interface{}
+	indexList []string
+	typeList  []string
+	size      int
+	keepAlive string
+
+	pitID   string
+	after   []json.RawMessage
+	started bool
+	last    bool
+	done    bool
+	err     error
+
+	hits []Hit
+	// position of the current hit in hits
+	pos int
+}
+
+// NewSearchAfterIterator returns a SearchAfterIterator over the hits of query
+// sorted by sort, fetched size at a time. A point in time kept alive for
+// keepAlive between two requests, e.g. "1m", is used if keepAlive is not empty
+// and the server supports it. No request is sent until Next is called.
+func (c *Client) NewSearchAfterIterator(query interface{}, sort []interface{}, indexList []string, typeList []string, size int, keepAlive string) *SearchAfterIterator {
+	return c.NewSearchAfterIteratorContext(context.Background(), query, sort, indexList, typeList, size, keepAlive)
+}
+
+// NewSearchAfterIteratorContext is the same as NewSearchAfterIterator, but the requests are bound to ctx
+func (c *Client) NewSearchAfterIteratorContext(ctx context.Context, query interface{}, sort []interface{}, indexList []string, typeList []string, size int, keepAlive string) *SearchAfterIterator {
+	return &SearchAfterIterator{
+		client:    c,
+		ctx:       ctx,
+		query:     query,
+		sort:      sort,
+		indexList: indexList,
+		typeList:  typeList,
+		size:      size,
+		keepAlive: keepAlive,
+		pos:       -1,
+	}
+}
+
+// Next moves to the next hit, fetching the next page when needed. It returns
+// false once there are no more hits or an error happened.
+func (it *SearchAfterIterator) Next() bool {
+	if it.err != nil {
+		return false
+	}
+
+	it.pos++
+	for it.pos >= len(it.hits) {
+		if it.done {
+			return false
+		}
+		if err := it.fetch(); err != nil {
+			it.err = err
+			it.Close()
+			return false
+		}
+	}
+	return true
+}
+
+// fetch replaces the hits with the ones of the next page
+func (it *SearchAfterIterator) fetch() error {
+	if it.last {
+		it.hits = nil
+		return it.Close()
+	}
+
+	if !it.started {
+		it.started = true
+		if err := it.openPointInTime(); err != nil {
+			return err
+		}
+	}
+
+	body, err := it.body()
+	if err != nil {
+		return err
+	}
+	r := Request{
+		Method: "POST",
+		API:    "_search",
+		Body:   body,
+	}
+	if it.pitID == "" {
+		// The indices of the search are the ones of the point in time otherwise
+		r.IndexList = it.indexList
+		r.TypeList = it.client.typeList(it.ctx, it.typeList)
+	}
+
+	resp, err := it.client.DoContext(it.ctx, &r)
+	if err != nil {
+		return err
+	}
+	if resp.PitID != "" {
+		it.pitID = resp.PitID
+	}
+
+	it.hits = resp.Hits.Hits
+	it.pos = 0
+	if len(it.hits) == 0 {
+		return it.Close()
+	}
+
+	it.after = it.hits[len(it.hits)-1].Sort
+	// A partial page is the last one, no need to ask for another
+	it.last = it.size > 0 && len(it.hits) < it.size
+	return nil
+}
+
+// openPointInTime opens the point in time of the iterator when it uses one
+func (it *SearchAfterIterator) openPointInTime() error {
+	if it.keepAlive == "" {
+		return nil
+	}
+
+	version, err := it.client.ServerVersionContext(it.ctx)
+	if err != nil {
+		return err
+	}
+	if !version.Supports(SupportsPointInTime) {
+		return nil
+	}
+
+	it.pitID, err = it.client.OpenPointInTimeContext(it.ctx, it.indexList, it.keepAlive)
+	return err
+}
+
+// body returns the body of the search for the next page, the query along with
+// the sort, size, search_after and pit parameters
+func (it *SearchAfterIterator) body() ([]byte, error) {
+	body, err := queryMap(it.query)
+	if err != nil {
+		return nil, err
+	}
+
+	body["sort"] = it.sort
+	if it.size > 0 {
+		body["size"] = it.size
+	}
+	if it.after != nil {
+		body["search_after"] = it.after
+	}
+	if it.pitID != "" {
+		body["pit"] = map[string]string{"id": it.pitID, "keep_alive": it.keepAlive}
+	}
+
+	return json.Marshal(body)
+}
+
+// Hit returns the current hit, nil before the first call to Next or once
+// there are no more hits
+func (it *SearchAfterIterator) Hit() *Hit {
+	if it.pos < 0 || it.pos >= len(it.hits) {
+		return nil
+	}
+	return &it.hits[it.pos]
+}
+
+// Hits returns the current hit along with the following ones of the same
+// page, and moves to the last of them. It allows handling the hits in batches.
+func (it *SearchAfterIterator) Hits() []Hit {
+	if it.pos < 0 || it.pos >= len(it.hits) {
+		return nil
+	}
+	hits := it.hits[it.pos:]
+	it.pos = len(it.hits) - 1
+	return hits
+}
+
+// Err returns the error which stopped the iteration, if any
+func (it *SearchAfterIterator) Err() error {
+	return it.err
+}
+
+// Close closes the point in time of the iterator, if any. It is called once
+// all the hits were read, but must be called when stopping before that.
+func (it *SearchAfterIterator) Close() error {
+	it.done = true
+	if it.pitID == "" {
+		return nil
+	}
+
+	pitID := it.pitID
+	it.pitID = ""
+	_, err := it.client.ClosePointInTimeContext(detachedContext{it.ctx}, pitID)
+	if searchErr, ok := err.(*SearchError); ok && searchErr.StatusCode == 404 {
+		// The point in time already expired
+		return nil
+	}
+	return err
+}
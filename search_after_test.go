@@ -0,0 +1,115 @@
+package goes
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+
+	. "github.com/go-check/check"
+)
+
+func (s *GoesTestSuite) TestSearchAfterIterator(c *C) {
+	query := map[string]interface{}{"query": map[string]interface{}{"match_all": map[string]interface{}{}}}
+	sort := []interface{}{map[string]string{"n": "asc"}}
+
+	for _, total := range []int{4, 5} {
+		server := &searchServer{version: "7.9.3", total: total}
+		ts := httptest.NewServer(server)
+
+		conn, err := NewClientFromURL(ts.URL)
+		c.Assert(err, IsNil)
+
+		it := conn.NewSearchAfterIterator(query, sort, []string{"i"}, nil, 2, "1m")
+		c.Assert(it.Hit(), IsNil)
+
+		ids := []string{}
+		for it.Next() {
+			ids = append(ids, it.Hit().ID)
+			c.Assert(it.Hit().Sort, HasLen, 1)
+		}
+		c.Assert(it.Err(), IsNil)
+		c.Assert(ids, HasLen, total)
+		c.Assert(ids[total-1], Equals, fmt.Sprint(total-1))
+		ts.Close()
+
+		// A partial page is the last one
+		c.Assert(server.bodies, HasLen, 3)
+		c.Assert(server.bodies[0], DeepEquals, map[string]interface{}{
+			"query": map[string]interface{}{"match_all": map[string]interface{}{}},
+			"sort":  []interface{}{map[string]interface{}{"n": "asc"}},
+			"size":  2.0,
+		})
+		c.Assert(server.bodies[2]["search_after"], DeepEquals, []interface{}{3.0})
+		c.Assert(server.paths, DeepEquals, []string{"/i/_search", "/i/_search", "/i/_search"})
+		c.Assert(server.closed, HasLen, 0)
+	}
+}
+
+func (s *GoesTestSuite) TestSearchAfterIteratorPointInTime(c *C) {
+	server := &searchServer{version: "7.10.2", total: 5}
+	ts := httptest.NewServer(server)
+	defer ts.Close()
+
+	conn, err := NewClientFromURL(ts.URL)
+	c.Assert(err, IsNil)
+
+	it := conn.NewSearchAfterIterator(nil, []interface{}{"n"}, []string{"i"}, nil, 2, "1m")
+	ids := []string{}
+	for it.Next() {
+		ids = append(ids, it.Hit().ID)
+	}
+	c.Assert(it.Err(), IsNil)
+	c.Assert(ids, DeepEquals, []string{"0", "1", "2", "3", "4"})
+
+	c.Assert(server.paths, DeepEquals, []string{"/_search", "/_search", "/_search"})
+	c.Assert(server.bodies[0]["pit"], DeepEquals, map[string]interface{}{"id": "pit0", "keep_alive": "1m"})
+	// The id of the point in time may change from a response to the next
+	c.Assert(server.bodies[1]["pit"], DeepEquals, map[string]interface{}{"id": "pit1", "keep_alive": "1m"})
+	c.Assert(server.closed, DeepEquals, []string{`{"id":"pit3"}`})
+
+	c.Assert(it.Close(), IsNil)
+	c.Assert(server.closed, HasLen, 1)
+}
+
+func (s *GoesTestSuite) TestSearchAfterIteratorClose(c *C) {
+	server := &searchServer{version: "8.1.0", total: 5}
+	ts := httptest.NewServer(server)
+	defer ts.Close()
+
+	conn, err := NewClientFromURL(ts.URL)
+	c.Assert(err, IsNil)
+
+	it := conn.NewSearchAfterIterator(nil, []interface{}{"n"}, []string{"i"}, nil, 2, "1m")
+	c.Assert(it.Next(), Equals, true)
+	c.Assert(it.Hits(), HasLen, 2)
+	c.Assert(it.Close(), IsNil)
+	c.Assert(it.Next(), Equals, false)
+	c.Assert(server.closed, DeepEquals, []string{`{"id":"pit1"}`})
+}
+
+func (s *GoesTestSuite) TestSearchAfterIteratorLongSortValues(c *C) {
+	var bodies []string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		bodies = append(bodies, string(body))
+		if len(bodies) == 1 {
+			w.Write([]byte(`{"hits":{"hits":[{"_id":"1","sort":[9223372036854775807,1234567890123456789,"a"]}]}}`))
+		} else {
+			w.Write([]byte(`{"hits":{"hits":[]}}`))
+		}
+	}))
+	defer ts.Close()
+
+	conn, err := NewClientFromURL(ts.URL)
+	c.Assert(err, IsNil)
+
+	it := conn.NewSearchAfterIterator(nil, []interface{}{"n", "m", "id"}, []string{"i"}, nil, 1, "")
+	for it.Next() {
+	}
+	c.Assert(it.Err(), IsNil)
+
+	c.Assert(bodies, HasLen, 2)
+	c.Assert(strings.Contains(bodies[1], `"search_after":[9223372036854775807,1234567890123456789,"a"]`), Equals, true, Commentf("%s", bodies[1]))
+}
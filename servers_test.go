@@ -0,0 +1,171 @@
+package goes
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// requestRecorder records the method, path and body of the requests it
+// serves. GET / is answered with version, the other requests with response,
+// or an empty object when not set.
+type requestRecorder struct {
+	version  string
+	response string
+
+	sync.Mutex
+	requests []string
+}
+
+func (s *requestRecorder) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path == "/" && s.version != "" {
+		fmt.Fprintf(w, `{"version":{"number":"%s"}}`, s.version)
+		return
+	}
+
+	body, _ := ioutil.ReadAll(r.Body)
+	s.Lock()
+	s.requests = append(s.requests, strings.TrimSpace(r.Method+" "+r.URL.Path+" "+string(body)))
+	s.Unlock()
+
+	switch {
+	case s.response != "":
+		w.Write([]byte(s.response))
+	case strings.HasSuffix(r.URL.Path, "/_msearch"):
+		w.Write([]byte(`{"responses":[{}]}`))
+	default:
+		w.Write([]byte(`{}`))
+	}
+}
+
+// searchServer serves total hits sorted by their "n" field, the way the given
+// version of elasticsearch does, to searches paged with a scroll, a sliced
+// scroll or search_after. Points in time are supported since 7.10.
+//
+// Scrolls are paged two hits at a time. Every slice of a sliced scroll gets
+// total hits, whose ids are prefixed by the slice.
+type searchServer struct {
+	version string
+	total   int
+
+	// Called before a page of a scroll is served, the scroll failing when it
+	// returns false. The slice is negative when the scroll is not sliced.
+	onScroll func(slice, page int) bool
+
+	sync.Mutex
+	// paths and bodies of the searches, their slices when sliced
+	paths  []string
+	bodies []map[string]interface{}
+	slices []int
+	// bodies of the requests clearing scrolls and closing points in time
+	cleared []string
+	closed  []string
+}
+
+func (s *searchServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, _ := ioutil.ReadAll(r.Body)
+
+	switch {
+	case r.URL.Path == "/":
+		fmt.Fprintf(w, `{"version":{"number":"%s"}}`, s.version)
+	case r.URL.Path == "/i/_pit" && r.Method == "POST":
+		w.Write([]byte(`{"id":"pit0"}`))
+	case r.URL.Path == "/_pit" && r.Method == "DELETE":
+		s.record(&s.closed, string(body))
+		w.Write([]byte(`{"succeeded":true}`))
+	case strings.HasSuffix(r.URL.Path, "/_search"):
+		var params map[string]interface{}
+		json.Unmarshal(body, &params)
+		s.Lock()
+		s.paths = append(s.paths, r.URL.Path)
+		s.bodies = append(s.bodies, params)
+		s.Unlock()
+
+		if r.URL.Query().Get("scroll") == "" {
+			s.searchAfter(w, params)
+			return
+		}
+		if r.URL.Query().Get("search_type") == "scan" {
+			// Scans start with no hits
+			fmt.Fprintf(w, `{"_scroll_id":"scroll0","hits":{"total":%d,"hits":[]}}`, s.total)
+			return
+		}
+		slice := -1
+		if sliceParams, ok := params["slice"].(map[string]interface{}); ok {
+			slice = int(sliceParams["id"].(float64))
+			s.Lock()
+			s.slices = append(s.slices, slice)
+			s.Unlock()
+		}
+		s.page(w, slice, 0)
+	case r.URL.Path == "/_search/scroll" && r.Method == "POST":
+		scrollID := r.URL.Query().Get("scroll_id")
+		if scrollID == "" {
+			var params map[string]string
+			json.Unmarshal(body, &params)
+			scrollID = params["scroll_id"]
+		}
+		slice, page := -1, 0
+		if _, err := fmt.Sscanf(scrollID, "scroll%d-%d", &slice, &page); err != nil {
+			slice = -1
+			fmt.Sscanf(scrollID, "scroll%d", &page)
+		}
+		if s.onScroll != nil && !s.onScroll(slice, page) {
+			w.WriteHeader(http.StatusInternalServerError)
+			w.Write([]byte(`{"error":"scroll failed","status":500}`))
+			return
+		}
+		s.page(w, slice, page)
+	case r.URL.Path == "/_search/scroll" && r.Method == "DELETE":
+		s.record(&s.cleared, string(body))
+		w.Write([]byte(`{"succeeded":true}`))
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+// record appends value to list under the lock of the server
+func (s *searchServer) record(list *[]string, value string) {
+	s.Lock()
+	*list = append(*list, value)
+	s.Unlock()
+}
+
+// page writes the hits of a page of a scroll along with the scroll id
+// fetching the next one
+func (s *searchServer) page(w http.ResponseWriter, slice, page int) {
+	prefix := ""
+	if slice >= 0 {
+		prefix = fmt.Sprintf("%d-", slice)
+	}
+
+	hits := []string{}
+	for i := page * 2; i < page*2+2 && i < s.total; i++ {
+		hits = append(hits, fmt.Sprintf(`{"_index":"i","_type":"t","_id":"%s%d","_source":{"n":%d}}`, prefix, i, i))
+	}
+	fmt.Fprintf(w, `{"_scroll_id":"scroll%s%d","hits":{"total":%d,"hits":[%s]}}`, prefix, page+1, s.total, strings.Join(hits, ","))
+}
+
+// searchAfter writes the page following the search_after parameter of a
+// search, along with a new point in time id when one is used
+func (s *searchServer) searchAfter(w http.ResponseWriter, params map[string]interface{}) {
+	from := 0
+	if after, ok := params["search_after"].([]interface{}); ok {
+		from = int(after[0].(float64)) + 1
+	}
+	hits := []string{}
+	for i := from; i < from+int(params["size"].(float64)) && i < s.total; i++ {
+		hits = append(hits, fmt.Sprintf(`{"_index":"i","_type":"t","_id":"%d","_source":{"n":%d},"sort":[%d]}`, i, i, i))
+	}
+
+	pitID := ""
+	if _, ok := params["pit"]; ok {
+		s.Lock()
+		pitID = fmt.Sprintf("pit%d", len(s.bodies))
+		s.Unlock()
+	}
+	fmt.Fprintf(w, `{"pit_id":"%s","hits":{"total":%d,"hits":[%s]}}`, pitID, s.total, strings.Join(hits, ","))
+}
@@ -0,0 +1,100 @@
+package goes
+
+import (
+	"context"
+	"sync"
+)
+
+// ScrollSlices reads all the hits of query with a sliced scroll, for large
+// exports to not be bound to a single cursor. slices scrolls, each over its
+// own slice of the hits, run in parallel and fn is called with every hit.
+// Sliced scrolls are available since 5.0, a single scroll is used with older
+// servers or when slices is lower than 2.
+//
+// fn is called from a single goroutine, in no particular order. Up to size
+// hits are buffered while it runs. The first error returned by fn or by a
+// slice stops all the slices and is returned. The search contexts of the
+// slices are cleared in any case.
+func (c *Client) ScrollSlices(query interface{}, indexList []string, typeList []string, timeout string, size int, slices int, fn func(Hit) error) error {
+	return c.ScrollSlicesContext(context.Background(), query, indexList, typeList, timeout, size, slices, fn)
+}
+
+// ScrollSlicesContext is the same as ScrollSlices, but the requests are bound to ctx
+func (c *Client) ScrollSlicesContext(ctx context.Context, query interface{}, indexList []string, typeList []string, timeout string, size int, slices int, fn func(Hit) error) error {
+	if slices < 2 {
+		slices = 1
+	} else if version, err := c.ServerVersionContext(ctx); err != nil {
+		return err
+	} else if !version.Supports(SupportsSlicedScroll) {
+		slices = 1
+	}
+
+	// The slices are stopped through ctx
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	iterators := make([]*ScrollIterator, slices)
+	for i := range iterators {
+		sliceQuery := query
+		if slices > 1 {
+			q, err := queryMap(query)
+			if err != nil {
+				return err
+			}
+			q["slice"] = map[string]int{"id": i, "max": slices}
+			sliceQuery = q
+		}
+		iterators[i] = c.NewScrollIteratorContext(ctx, sliceQuery, indexList, typeList, timeout, size)
+	}
+
+	hits := make(chan Hit, size)
+	// errs receives the error stopping a slice, the first one being the
+	// cause of the cancellation of the others
+	errs := make(chan error, slices)
+
+	var wg sync.WaitGroup
+	for _, it := range iterators {
+		wg.Add(1)
+		go func(it *ScrollIterator) {
+			defer wg.Done()
+			defer it.Close()
+
+			for it.Next() {
+				select {
+				case hits <- *it.Hit():
+				case <-ctx.Done():
+					errs <- ctx.Err()
+					return
+				}
+			}
+			if err := it.Err(); err != nil {
+				errs <- err
+				cancel()
+			}
+		}(it)
+	}
+	go func() {
+		wg.Wait()
+		close(hits)
+	}()
+
+	var err error
+	for hit := range hits {
+		if err != nil {
+			// The hits still sent by the slices being stopped are dropped
+			continue
+		}
+		if err = fn(hit); err != nil {
+			cancel()
+		}
+	}
+	if err != nil {
+		return err
+	}
+
+	select {
+	case err = <-errs:
+	default:
+	}
+	return err
+}
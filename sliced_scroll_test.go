@@ -0,0 +1,111 @@
+package goes
+
+import (
+	"errors"
+	"net/http/httptest"
+	"sort"
+
+	. "github.com/go-check/check"
+)
+
+// failingSlice returns a hook of searchServer failing the scroll of slice
+// once the other slices of a scroll over 3 slices are scrolling too
+func failingSlice(slice int) func(int, int) bool {
+	// receives a value for the first scroll request of the slices which do not fail
+	scrolls := make(chan struct{}, 16)
+	return func(s, page int) bool {
+		if s != slice {
+			if page == 1 {
+				scrolls <- struct{}{}
+			}
+			return true
+		}
+		<-scrolls
+		<-scrolls
+		return false
+	}
+}
+
+func (s *GoesTestSuite) TestScrollSlices(c *C) {
+	server := &searchServer{version: "7.10.2", total: 3}
+	ts := httptest.NewServer(server)
+	defer ts.Close()
+
+	conn, err := NewClientFromURL(ts.URL)
+	c.Assert(err, IsNil)
+
+	ids := []string{}
+	err = conn.ScrollSlices(map[string]interface{}{"query": map[string]interface{}{"match_all": map[string]interface{}{}}},
+		[]string{"i"}, nil, "1m", 2, 3, func(hit Hit) error {
+			ids = append(ids, hit.ID)
+			return nil
+		})
+	c.Assert(err, IsNil)
+
+	sort.Strings(ids)
+	c.Assert(ids, DeepEquals, []string{"0-0", "0-1", "0-2", "1-0", "1-1", "1-2", "2-0", "2-1", "2-2"})
+	sort.Ints(server.slices)
+	c.Assert(server.slices, DeepEquals, []int{0, 1, 2})
+	sort.Strings(server.cleared)
+	c.Assert(server.cleared, DeepEquals, []string{`{"scroll_id":["scroll0-3"]}`, `{"scroll_id":["scroll1-3"]}`, `{"scroll_id":["scroll2-3"]}`})
+}
+
+func (s *GoesTestSuite) TestScrollSlicesError(c *C) {
+	server := &searchServer{version: "7.10.2", total: 3, onScroll: failingSlice(1)}
+	ts := httptest.NewServer(server)
+	defer ts.Close()
+
+	conn, err := NewClientFromURL(ts.URL)
+	c.Assert(err, IsNil)
+
+	err = conn.ScrollSlices(nil, []string{"i"}, nil, "1m", 2, 3, func(hit Hit) error { return nil })
+	c.Assert(err, ErrorMatches, `\[500\] scroll failed`)
+
+	server.Lock()
+	cleared := server.cleared
+	server.Unlock()
+	c.Assert(cleared, HasLen, 3)
+
+	server = &searchServer{version: "7.10.2", total: 3}
+	ts2 := httptest.NewServer(server)
+	defer ts2.Close()
+
+	conn, err = NewClientFromURL(ts2.URL)
+	c.Assert(err, IsNil)
+
+	// Stopping once every slice got its scroll id
+	slices := map[string]bool{}
+	err = conn.ScrollSlices(nil, []string{"i"}, nil, "1m", 2, 3, func(hit Hit) error {
+		slices[hit.ID[:1]] = true
+		if len(slices) == 3 {
+			return errors.New("stop")
+		}
+		return nil
+	})
+	c.Assert(err, ErrorMatches, "stop")
+
+	server.Lock()
+	cleared = server.cleared
+	server.Unlock()
+	c.Assert(cleared, HasLen, 3)
+}
+
+func (s *GoesTestSuite) TestScrollSlicesUnsupported(c *C) {
+	server := &searchServer{version: "2.4.4", total: 3}
+	ts := httptest.NewServer(server)
+	defer ts.Close()
+
+	conn, err := NewClientFromURL(ts.URL)
+	c.Assert(err, IsNil)
+
+	// A single scroll is used before 5.0
+	ids := []string{}
+	err = conn.ScrollSlices(nil, []string{"i"}, nil, "1m", 2, 3, func(hit Hit) error {
+		ids = append(ids, hit.ID)
+		return nil
+	})
+	c.Assert(err, IsNil)
+	c.Assert(ids, DeepEquals, []string{"0", "1", "2"})
+	c.Assert(server.slices, HasLen, 0)
+	c.Assert(server.cleared, HasLen, 1)
+}
@@ -0,0 +1,180 @@
+package goes
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// nodesInfo holds the parts of a _nodes/http response needed to sniff a cluster
+type nodesInfo struct {
+	Nodes map[string]nodeInfo `json:"nodes"`
+}
+
+// nodeInfo describes a single node of a _nodes/http response
+type nodeInfo struct {
+	// Roles of the node, as returned by ES 5.x and above
+	Roles []string `json:"roles"`
+
+	// Attributes of the node. ES 1.x and 2.x report non data nodes with a
+	// "data": "false" attribute instead of roles.
+	Attributes map[string]interface{} `json:"attributes"`
+
+	HTTP struct {
+		PublishAddress string `json:"publish_address"`
+	} `json:"http"`
+}
+
+// holdsData reports whether requests may be sent to the node: data and ingest
+// nodes are kept, master only and client nodes are left aside
+func (n nodeInfo) holdsData() bool {
+	if n.Roles != nil {
+		for _, role := range n.Roles {
+			// 7.x introduced data tiers such as data_hot or data_content
+			if role == "ingest" || strings.HasPrefix(role, "data") {
+				return true
+			}
+		}
+		return false
+	}
+
+	data, _ := n.Attributes["data"].(string)
+	return data != "false"
+}
+
+// parsePublishAddress turns the publish_address of a node into a "host:port" address.
+// ES 1.x reports it as "inet[/127.0.0.1:9200]", later versions as "127.0.0.1:9200",
+// optionally prefixed by the host name as in "es1.local/127.0.0.1:9200".
+func parsePublishAddress(address string) string {
+	if strings.HasPrefix(address, "inet[") {
+		address = strings.TrimSuffix(strings.TrimPrefix(address, "inet["), "]")
+	}
+
+	i := strings.Index(address, "/")
+	if i < 0 {
+		return address
+	}
+
+	host, hostPort := address[:i], address[i+1:]
+	if host == "" {
+		return hostPort
+	}
+	_, port, err := net.SplitHostPort(hostPort)
+	if err != nil {
+		return hostPort
+	}
+	return net.JoinHostPort(host, port)
+}
+
+// Sniff asks the cluster for its HTTP enabled nodes and replaces the nodes the
+// client sends requests to by the data and ingest nodes found.
+func (c *Client) Sniff() error {
+	return c.SniffContext(context.Background())
+}
+
+// SniffContext is the same as Sniff, but the request is bound to ctx
+func (c *Client) SniffContext(ctx context.Context) error {
+	r := Request{
+		Method: "GET",
+		API:    "_nodes/http",
+	}
+
+	body, _, err := c.DoRawContext(ctx, &r)
+	if err != nil {
+		return err
+	}
+
+	var info nodesInfo
+	if err := json.Unmarshal(body, &info); err != nil {
+		return err
+	}
+
+	// Sniffed nodes are reached the same way as the known ones
+	base := url.URL{Scheme: "http"}
+	if n := c.pickNode(); n != nil {
+		base = *n.url
+	}
+
+	urls := make([]*url.URL, 0, len(info.Nodes))
+	for _, n := range info.Nodes {
+		if !n.holdsData() || n.HTTP.PublishAddress == "" {
+			continue
+		}
+		u := base
+		u.Host = parsePublishAddress(n.HTTP.PublishAddress)
+		urls = append(urls, &u)
+	}
+
+	if len(urls) == 0 {
+		return errors.New("No HTTP enabled data node returned by ElasticSearch Server")
+	}
+
+	c.poolLock.Lock()
+	defer c.poolLock.Unlock()
+	if c.pool == nil {
+		c.pool = newNodePool(urls)
+	} else {
+		c.pool.replace(urls)
+	}
+	return nil
+}
+
+// StartSniffer sniffs the cluster nodes right away then again every interval
+// in a background goroutine, until Close is called. Errors happening after the
+// first sniff are ignored and the previous set of nodes is kept, as are the
+// nodes when the cluster does not answer within interval.
+func (c *Client) StartSniffer(interval time.Duration) error {
+	c.poolLock.Lock()
+	if c.pool == nil {
+		c.pool = newNodePool([]*url.URL{{Scheme: "http", Host: net.JoinHostPort(c.Host, c.Port)}})
+	}
+	c.poolLock.Unlock()
+
+	if err := c.Sniff(); err != nil {
+		return err
+	}
+
+	stop := c.stopChan()
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				ctx, cancel := context.WithTimeout(context.Background(), interval)
+				c.SniffContext(ctx)
+				cancel()
+			case <-stop:
+				return
+			}
+		}
+	}()
+
+	return nil
+}
+
+// stopChan returns the channel closed by Close to stop background goroutines
+func (c *Client) stopChan() chan struct{} {
+	c.poolLock.Lock()
+	defer c.poolLock.Unlock()
+	if c.stop == nil {
+		c.stop = make(chan struct{})
+	}
+	return c.stop
+}
+
+// Close stops the background goroutines started by the client, such as the
+// sniffer. It may be called several times, even concurrently.
+func (c *Client) Close() {
+	c.poolLock.Lock()
+	defer c.poolLock.Unlock()
+	if c.stop != nil {
+		close(c.stop)
+		c.stop = nil
+	}
+}
@@ -0,0 +1,166 @@
+package goes
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	. "github.com/go-check/check"
+)
+
+var nodesHTTPResponses = []struct {
+	version  string
+	body     string
+	expected []string
+}{
+	{
+		"1.7",
+		`{"cluster_name":"es","nodes":{
+			"a":{"name":"a","http_address":"inet[/10.0.0.1:9200]","attributes":{"master":"true"},"http":{"bound_address":"inet[/0:0:0:0:0:0:0:0:9200]","publish_address":"inet[/10.0.0.1:9200]"}},
+			"b":{"name":"b","http_address":"inet[es2.local/10.0.0.2:9200]","attributes":{"data":"false","client":"true"},"http":{"bound_address":"inet[/0:0:0:0:0:0:0:0:9200]","publish_address":"inet[es2.local/10.0.0.2:9200]"}}
+		}}`,
+		[]string{"10.0.0.1:9200"},
+	},
+	{
+		"2.4",
+		`{"cluster_name":"es","nodes":{
+			"a":{"name":"a","http_address":"10.0.0.1:9200","attributes":{"data":"false","master":"true"},"http":{"bound_address":["[::]:9200"],"publish_address":"10.0.0.1:9200"}},
+			"b":{"name":"b","http_address":"10.0.0.2:9200","http":{"bound_address":["[::]:9200"],"publish_address":"10.0.0.2:9200"}}
+		}}`,
+		[]string{"10.0.0.2:9200"},
+	},
+	{
+		"5.6",
+		`{"_nodes":{"total":3,"successful":3,"failed":0},"cluster_name":"es","nodes":{
+			"a":{"name":"a","roles":["master"],"attributes":{"data":"true"},"http":{"bound_address":["[::]:9200"],"publish_address":"10.0.0.1:9200"}},
+			"b":{"name":"b","roles":["data"],"http":{"bound_address":["[::]:9200"],"publish_address":"10.0.0.2:9200"}},
+			"c":{"name":"c","roles":["ingest"],"http":{"bound_address":["[::]:9200"],"publish_address":"10.0.0.3:9200"}}
+		}}`,
+		[]string{"10.0.0.2:9200", "10.0.0.3:9200"},
+	},
+	{
+		"7.10",
+		`{"_nodes":{"total":3,"successful":3,"failed":0},"cluster_name":"es","nodes":{
+			"a":{"name":"a","roles":["master","remote_cluster_client"],"http":{"bound_address":["[::]:9200"],"publish_address":"es1.local/10.0.0.1:9200"}},
+			"b":{"name":"b","roles":["data_hot","data_content","ingest"],"http":{"bound_address":["[::]:9200"],"publish_address":"es2.local/10.0.0.2:9200"}},
+			"c":{"name":"c","roles":["data","master"],"http":{"bound_address":["[::]:9200"],"publish_address":"10.0.0.3:9200"}}
+		}}`,
+		[]string{"10.0.0.3:9200", "es2.local:9200"},
+	},
+}
+
+func (s *GoesTestSuite) TestSniffResponses(c *C) {
+	for _, t := range nodesHTTPResponses {
+		var info nodesInfo
+		c.Assert(json.Unmarshal([]byte(t.body), &info), IsNil, Commentf("version %s", t.version))
+
+		addresses := []string{}
+		for _, n := range info.Nodes {
+			if n.holdsData() {
+				addresses = append(addresses, parsePublishAddress(n.HTTP.PublishAddress))
+			}
+		}
+		sort.Strings(addresses)
+
+		c.Assert(addresses, DeepEquals, t.expected, Commentf("version %s", t.version))
+	}
+}
+
+func (s *GoesTestSuite) TestSniff(c *C) {
+	var ts *httptest.Server
+	ts = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		c.Check(r.URL.Path, Equals, "/_nodes/http")
+		address := strings.TrimPrefix(ts.URL, "http://")
+		fmt.Fprintf(w, `{"nodes":{"a":{"roles":["data"],"http":{"publish_address":"%s"}},"b":{"roles":["master"],"http":{"publish_address":"10.0.0.9:9200"}}}}`, address)
+	}))
+	defer ts.Close()
+
+	address := strings.TrimPrefix(ts.URL, "http://")
+	conn := NewClusterClient([]string{"127.0.0.1:1", address})
+	defer conn.Close()
+
+	err := conn.StartSniffer(time.Hour)
+	c.Assert(err, IsNil)
+
+	c.Assert(conn.pool.nodes, HasLen, 1)
+	c.Assert(conn.pool.nodes[0].url.Host, Equals, address)
+}
+
+func (s *GoesTestSuite) TestSniffWhileRequesting(c *C) {
+	var ts *httptest.Server
+	ts = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		address := strings.TrimPrefix(ts.URL, "http://")
+		fmt.Fprintf(w, `{"nodes":{"a":{"roles":["data"],"http":{"publish_address":"%s"}}}}`, address)
+	}))
+	defer ts.Close()
+
+	u, err := url.Parse(ts.URL)
+	c.Assert(err, IsNil)
+	conn := NewClient(u.Hostname(), u.Port())
+
+	// The pool of a client created with NewClient is set by the first sniff
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 20; i++ {
+			_, _, err := conn.DoRaw(&Request{Method: "GET"})
+			c.Check(err, IsNil)
+		}
+	}()
+	c.Assert(conn.Sniff(), IsNil)
+	<-done
+
+	c.Assert(conn.NodeStates(), HasLen, 1)
+}
+
+func (s *GoesTestSuite) TestSniffContext(c *C) {
+	var count int
+	ts := newCountingServer(&count)
+	defer ts.Close()
+
+	address := strings.TrimPrefix(ts.URL, "http://")
+	conn := NewClusterClient([]string{address})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	c.Assert(conn.SniffContext(ctx), ErrorMatches, ".*context canceled")
+	c.Assert(count, Equals, 0)
+	c.Assert(conn.pool.nodes, HasLen, 1)
+	c.Assert(conn.pool.nodes[0].url.Host, Equals, address)
+}
+
+func (s *GoesTestSuite) TestCloseConcurrently(c *C) {
+	var ts *httptest.Server
+	ts = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		address := strings.TrimPrefix(ts.URL, "http://")
+		fmt.Fprintf(w, `{"nodes":{"a":{"roles":["data"],"http":{"publish_address":"%s"}}}}`, address)
+	}))
+	defer ts.Close()
+
+	conn := NewClusterClient([]string{strings.TrimPrefix(ts.URL, "http://")})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			c.Check(conn.StartSniffer(time.Millisecond), IsNil)
+		}()
+		go func() {
+			defer wg.Done()
+			conn.Close()
+		}()
+	}
+	wg.Wait()
+
+	conn.Close()
+	conn.Close()
+}
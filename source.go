@@ -0,0 +1,71 @@
+package goes
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+)
+
+// UnmarshalJSON decodes a response, keeping its _source in RawSource
+func (r *Response) UnmarshalJSON(data []byte) error {
+	type response Response
+	var fields struct {
+		*response
+		// Shadows Source, which is decoded from it
+		RawSource json.RawMessage `json:"_source"`
+	}
+	fields.response = (*response)(r)
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return err
+	}
+
+	r.RawSource, r.Source = nil, nil
+	if !isNull(fields.RawSource) {
+		r.RawSource = fields.RawSource
+		return json.Unmarshal(r.RawSource, &r.Source)
+	}
+	return nil
+}
+
+// Decode decodes the _source returned by the GET API into v, as
+// json.Unmarshal does
+func (r *Response) Decode(v interface{}) error {
+	if len(r.RawSource) == 0 {
+		return errors.New("Response has no _source")
+	}
+	return json.Unmarshal(r.RawSource, v)
+}
+
+// UnmarshalJSON decodes a hit, keeping its _source in RawSource
+func (h *Hit) UnmarshalJSON(data []byte) error {
+	type hit Hit
+	var fields struct {
+		*hit
+		// Shadows Source, which is decoded from it
+		RawSource json.RawMessage `json:"_source"`
+	}
+	fields.hit = (*hit)(h)
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return err
+	}
+
+	h.RawSource, h.Source = nil, nil
+	if !isNull(fields.RawSource) {
+		h.RawSource = fields.RawSource
+		return json.Unmarshal(h.RawSource, &h.Source)
+	}
+	return nil
+}
+
+// Decode decodes the _source of the hit into v, as json.Unmarshal does
+func (h *Hit) Decode(v interface{}) error {
+	if len(h.RawSource) == 0 {
+		return errors.New("Hit has no _source")
+	}
+	return json.Unmarshal(h.RawSource, v)
+}
+
+// isNull returns whether a raw JSON value is missing or null
+func isNull(raw json.RawMessage) bool {
+	return len(raw) == 0 || bytes.Equal(raw, []byte("null"))
+}
@@ -0,0 +1,55 @@
+//go:build go1.18
+// +build go1.18
+
+package goes
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+)
+
+// SearchInto runs a search like Client.Search and decodes the _source of
+// every hit into a T
+func SearchInto[T any](c *Client, query interface{}, indexList []string, typeList []string, extraArgs url.Values) ([]T, error) {
+	return SearchIntoContext[T](context.Background(), c, query, indexList, typeList, extraArgs)
+}
+
+// SearchIntoContext is the same as SearchInto, but the request is bound to ctx
+func SearchIntoContext[T any](ctx context.Context, c *Client, query interface{}, indexList []string, typeList []string, extraArgs url.Values) ([]T, error) {
+	resp, err := c.SearchContext(ctx, query, indexList, typeList, extraArgs)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]T, len(resp.Hits.Hits))
+	for i := range resp.Hits.Hits {
+		if err := resp.Hits.Hits[i].Decode(&results[i]); err != nil {
+			return nil, fmt.Errorf("Hit %s: %s", resp.Hits.Hits[i].ID, err)
+		}
+	}
+	return results, nil
+}
+
+// GetInto gets a document like Client.Get and decodes its _source into a T.
+// A SearchError with a 404 status code is returned when the document is not
+// found.
+func GetInto[T any](c *Client, index string, documentType string, id string, extraArgs url.Values) (T, error) {
+	return GetIntoContext[T](context.Background(), c, index, documentType, id, extraArgs)
+}
+
+// GetIntoContext is the same as GetInto, but the request is bound to ctx
+func GetIntoContext[T any](ctx context.Context, c *Client, index string, documentType string, id string, extraArgs url.Values) (T, error) {
+	var result T
+
+	resp, err := c.GetContext(ctx, index, documentType, id, extraArgs)
+	if err != nil {
+		return result, err
+	}
+	if !resp.Found {
+		return result, &SearchError{fmt.Sprintf("Document %s not found in %s", id, index), resp.Status}
+	}
+
+	err = resp.Decode(&result)
+	return result, err
+}
@@ -0,0 +1,50 @@
+//go:build go1.18
+// +build go1.18
+
+package goes
+
+import (
+	"net/http/httptest"
+
+	. "github.com/go-check/check"
+)
+
+func (s *GoesTestSuite) TestSearchInto(c *C) {
+	ts := httptest.NewServer(sourceServer{})
+	defer ts.Close()
+
+	conn, err := NewClientFromURL(ts.URL)
+	c.Assert(err, IsNil)
+
+	docs, err := SearchInto[sourceDocument](conn, nil, []string{"i"}, nil, nil)
+	c.Assert(err, IsNil)
+	c.Assert(docs, DeepEquals, []sourceDocument{{ID: 9007199254740993, Name: "foo"}, {ID: 2, Name: "bar"}})
+
+	sources, err := SearchInto[map[string]interface{}](conn, nil, []string{"i"}, nil, nil)
+	c.Assert(err, IsNil)
+	c.Assert(sources, HasLen, 2)
+	c.Assert(sources[1]["name"], Equals, "bar")
+}
+
+func (s *GoesTestSuite) TestGetInto(c *C) {
+	ts := httptest.NewServer(sourceServer{})
+	defer ts.Close()
+
+	conn, err := NewClientFromURL(ts.URL)
+	c.Assert(err, IsNil)
+
+	doc, err := GetInto[sourceDocument](conn, "i", "_doc", "1", nil)
+	c.Assert(err, IsNil)
+	c.Assert(doc, Equals, sourceDocument{ID: 9007199254740993, Name: "foo"})
+
+	ptr, err := GetInto[*sourceDocument](conn, "i", "_doc", "1", nil)
+	c.Assert(err, IsNil)
+	c.Assert(*ptr, Equals, doc)
+
+	_, err = GetInto[sourceDocument](conn, "i", "_doc", "2", nil)
+	c.Assert(err, ErrorMatches, `\[404\] Document 2 not found in i`)
+	c.Assert(err.(*SearchError).StatusCode, Equals, uint64(404))
+
+	_, err = GetInto[sourceDocument](conn, "i", "_doc", "3", nil)
+	c.Assert(err, ErrorMatches, "json: cannot unmarshal string .*")
+}
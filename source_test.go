@@ -0,0 +1,73 @@
+package goes
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+
+	. "github.com/go-check/check"
+)
+
+// sourceServer serves a search and a GET of documents whose ids do not fit
+// in a float64
+type sourceServer struct{}
+
+func (sourceServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.URL.Path {
+	case "/":
+		w.Write([]byte(`{"version":{"number":"7.10.2"}}`))
+	case "/i/_search":
+		w.Write([]byte(`{"hits":{"total":{"value":2,"relation":"eq"},"hits":[
+			{"_index":"i","_id":"1","_source":{"id":9007199254740993,"name":"foo"}},
+			{"_index":"i","_id":"2","_source":{"id":2,"name":"bar"}}
+		]}}`))
+	case "/i/_doc/1":
+		w.Write([]byte(`{"_index":"i","_id":"1","found":true,"_source":{"id":9007199254740993,"name":"foo"}}`))
+	case "/i/_doc/2":
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"_index":"i","_id":"2","found":false}`))
+	case "/i/_doc/3":
+		w.Write([]byte(`{"_index":"i","_id":"3","found":true,"_source":{"id":"three"}}`))
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+type sourceDocument struct {
+	ID   int64
+	Name string
+}
+
+func (s *GoesTestSuite) TestDecodeSource(c *C) {
+	ts := httptest.NewServer(sourceServer{})
+	defer ts.Close()
+
+	conn, err := NewClientFromURL(ts.URL)
+	c.Assert(err, IsNil)
+
+	response, err := conn.Search(nil, []string{"i"}, nil, nil)
+	c.Assert(err, IsNil)
+	c.Assert(response.Hits.Hits, HasLen, 2)
+
+	var doc sourceDocument
+	c.Assert(response.Hits.Hits[0].Decode(&doc), IsNil)
+	c.Assert(doc, Equals, sourceDocument{ID: 9007199254740993, Name: "foo"})
+	// The map stays populated
+	c.Assert(response.Hits.Hits[1].Source, DeepEquals, map[string]interface{}{"id": 2.0, "name": "bar"})
+
+	response, err = conn.Get("i", "_doc", "1", nil)
+	c.Assert(err, IsNil)
+	c.Assert(response.Decode(&doc), IsNil)
+	c.Assert(doc, Equals, sourceDocument{ID: 9007199254740993, Name: "foo"})
+	c.Assert(response.Source["name"], Equals, "foo")
+
+	response, err = conn.Get("i", "_doc", "2", nil)
+	c.Assert(err, IsNil)
+	c.Assert(response.RawSource, IsNil)
+	c.Assert(response.Decode(&doc), ErrorMatches, "Response has no _source")
+
+	var hit Hit
+	c.Assert(json.Unmarshal([]byte(`{"_id":"1","_source":null}`), &hit), IsNil)
+	c.Assert(hit.Source, IsNil)
+	c.Assert(hit.Decode(&doc), ErrorMatches, "Hit has no _source")
+}
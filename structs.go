@@ -7,6 +7,8 @@ package goes
 import (
 	"encoding/json"
 	"net/http"
+	"sync"
+	"time"
 )
 
 // Client represents a connection to elasticsearch
@@ -21,8 +23,45 @@ type Client struct {
 	// such as timeouts etc
 	Client *http.Client
 
-	// Detected version of ES
-	version string
+	// Detected or pinned version of ES, nil until known
+	version     *ServerVersion
+	versionLock sync.Mutex
+	// Time before which a failed lookup of the version is not made again
+	versionRetry time.Time
+
+	// Nodes of the cluster when the client was created with NewClusterClient
+	// or the nodes were sniffed, set by the sniffer while requests are sent
+	pool     *nodePool
+	poolLock sync.Mutex
+
+	// Closed by Close to stop background goroutines, guarded by poolLock
+	stop chan struct{}
+
+	// Policy used to retry failed requests, requests are not retried when nil
+	retry *RetryPolicy
+
+	// Adds credentials to every request when set
+	auth Authenticator
+
+	// Error of the configuration of the client, returned by every request
+	err error
+
+	// Whether gzip compression is enabled, and the size from which request
+	// bodies are compressed
+	gzip          bool
+	gzipThreshold int
+}
+
+// NodeState describes the health of a node as seen by the client
+type NodeState struct {
+	URL   string
+	Alive bool
+
+	// Number of consecutive failed requests and health checks
+	Failures int
+
+	// When the node was marked as dead, zero for live nodes
+	DeadSince time.Time
 }
 
 // Response holds an elasticsearch response
@@ -38,7 +77,7 @@ type Response struct {
 	Hits         Hits
 	Index        string `json:"_index"`
 	ID           string `json:"_id"`
-	Type         string `json:"_type"`
+	Type         string `json:"_type"` // empty since 8.0
 	Version      int    `json:"_version"`
 	Found        bool
 	Count        int
@@ -49,17 +88,32 @@ type Response struct {
 	// Used by the _bulk API
 	Items []map[string]Item `json:"items,omitempty"`
 
+	// Used by the _msearch API
+	Responses []*Response `json:"responses,omitempty"`
+
+	// Used by the _mget API
+	Docs []*Response `json:"docs,omitempty"`
+
 	// Used by the GET API
 	Source map[string]interface{} `json:"_source"`
 	Fields map[string]interface{} `json:"fields"`
 
+	// _source as it was returned, decoded by Decode
+	RawSource json.RawMessage `json:"-"`
+
 	// Used by the _status API
 	Indices map[string]IndexStatus
 
 	// Scroll id for iteration
 	ScrollID string `json:"_scroll_id"`
 
-	Aggregations map[string]Aggregation `json:"aggregations,omitempty"`
+	// Point in time id of a search, since 7.10
+	PitID string `json:"pit_id"`
+
+	Aggregations Aggregations `json:"aggregations,omitempty"`
+
+	// Structured version of Error, nil when there is no error
+	Cause *ErrorCause `json:"-"`
 
 	Raw map[string]interface{}
 }
@@ -70,14 +124,96 @@ type Aggregation map[string]interface{}
 // Bucket represents a bucket for aggregation
 type Bucket map[string]interface{}
 
+// Aggregations holds the aggregations of a response or a bucket by name
+type Aggregations map[string]Aggregation
+
+// BucketAggregation holds the buckets of a multi-bucket aggregation, such
+// as terms or date_histogram
+type BucketAggregation struct {
+	// Used by the terms aggregation
+	DocCountErrorUpperBound int64
+	SumOtherDocCount        int64
+
+	Buckets []AggregationBucket
+}
+
+// AggregationBucket holds a bucket of a multi-bucket aggregation
+type AggregationBucket struct {
+	Key         interface{}
+	KeyAsString string
+	DocCount    uint64
+
+	// Sub-aggregations of the bucket
+	Aggregations Aggregations
+}
+
+// SingleBucket holds the result of a single bucket aggregation, such as
+// filter or nested
+type SingleBucket struct {
+	DocCount uint64
+
+	// Sub-aggregations of the bucket
+	Aggregations Aggregations
+}
+
+// MetricValue holds the result of a single value metric aggregation, such
+// as avg or cardinality, or of a pipeline aggregation
+type MetricValue struct {
+	// nil when there was no value to aggregate, e.g. for the avg of no document
+	Value         *float64
+	ValueAsString string
+}
+
+// Stats holds the result of a stats aggregation. Min, Max and Avg are nil
+// when there was no value to aggregate.
+type Stats struct {
+	Count uint64
+	Min   *float64
+	Max   *float64
+	Avg   *float64
+	Sum   float64
+}
+
+// Percentile holds the value of a percentile
+type Percentile struct {
+	Percent float64
+	// nil when there was no value to aggregate
+	Value *float64
+}
+
+// Percentiles holds the result of a percentiles aggregation, ordered by percent
+type Percentiles []Percentile
+
+// TopHits holds the result of a top_hits aggregation
+type TopHits struct {
+	Total    uint64
+	MaxScore *float64
+	Hits     []Hit
+}
+
 // Document holds a document to send to elasticsearch
 type Document struct {
 	// XXX : interface as we can support nil values
 	Index       interface{}
-	Type        string
+	Type        string // ignored by servers without types, since 7.0
 	ID          interface{}
 	BulkCommand string
 	Fields      interface{}
+
+	// Metadata of the document in a _bulk request, omitted when unset
+	Routing       string
+	Version       *int64
+	VersionType   string
+	IfSeqNo       *int64
+	IfPrimaryTerm *int64
+	Pipeline      string
+
+	// Used by the update bulk command. Fields holds the partial document to
+	// merge into the existing one.
+	DocAsUpsert     bool
+	Upsert          interface{}
+	Script          interface{}
+	RetryOnConflict int
 }
 
 // Item holds an item from the "items" field in a _bulk response
@@ -88,6 +224,46 @@ type Item struct {
 	Version int    `json:"_version"`
 	Error   string `json:"error"`
 	Status  uint64 `json:"status"`
+
+	// Structured version of Error, nil for successful items
+	Cause *ErrorCause `json:"-"`
+}
+
+// ErrorCause holds the details of an error returned by elasticsearch
+type ErrorCause struct {
+	Type      string       `json:"type"`
+	Reason    string       `json:"reason"`
+	Index     string       `json:"index,omitempty"`
+	CausedBy  *ErrorCause  `json:"caused_by,omitempty"`
+	RootCause []ErrorCause `json:"root_cause,omitempty"`
+}
+
+// BulkItemResult describes what happened to a single document of a _bulk request
+type BulkItemResult struct {
+	// Position of the document in the slice sent
+	Position int
+
+	// Bulk command of the document (index, delete ...)
+	Action string
+
+	Index  string
+	Type   string
+	ID     string
+	Status uint64
+
+	// Type and reason of the error, empty for successful documents
+	ErrorType   string
+	ErrorReason string
+
+	// The document as it was sent
+	Document Document
+}
+
+// BulkError is returned when some documents of a _bulk request failed. Both the
+// failed and the successful documents are listed.
+type BulkError struct {
+	Failed    []BulkItemResult
+	Succeeded []BulkItemResult
 }
 
 // All represents the "_all" field when calling the _stats API
@@ -119,17 +295,27 @@ type Shard struct {
 // Hit holds a hit returned by a search
 type Hit struct {
 	Index     string                 `json:"_index"`
-	Type      string                 `json:"_type"`
+	Type      string                 `json:"_type"` // empty since 8.0
 	ID        string                 `json:"_id"`
 	Score     float64                `json:"_score"`
 	Source    map[string]interface{} `json:"_source"`
 	Highlight map[string]interface{} `json:"highlight"`
 	Fields    map[string]interface{} `json:"fields"`
+	// Sort values of the hit, when the search is sorted, as they were
+	// returned so that longs keep their precision
+	Sort []json.RawMessage `json:"sort"`
+
+	// _source as it was returned, decoded by Decode
+	RawSource json.RawMessage `json:"-"`
 }
 
 // Hits holds the hits structure as returned by elasticsearch
 type Hits struct {
+	// Total number of hits, a lower bound when TotalRelation is "gte"
 	Total uint64
+	// "eq" or "gte" since 7.0, where the total may not be exact, and "eq"
+	// before. Empty when the total was not tracked.
+	TotalRelation string `json:"-"`
 	// max_score may contain the "null" value
 	MaxScore interface{} `json:"max_score"`
 	Hits     []Hit
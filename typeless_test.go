@@ -0,0 +1,148 @@
+package goes
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"time"
+
+	. "github.com/go-check/check"
+)
+
+var typelessRequests = []struct {
+	version  ServerVersion
+	expected []string
+}{
+	{
+		ServerVersion{Major: 6, Minor: 8},
+		[]string{
+			"GET /i/t/1",
+			`PUT /i/t/1/ {"n":1}`,
+			`POST /i/t/ {"n":1}`,
+			"DELETE /i/t/1/",
+			`POST /i/t/1/_update {"doc":{"n":2}}`,
+			"POST /i/t/_search",
+			"POST /i/t/_count",
+			`PUT /i/_mappings/t {"t":{"properties":{}}}`,
+			"GET /i/_mapping/t",
+			"POST /i/t/_search",
+			`POST /_bulk {"delete":{"_id":"1","_index":"i","_type":"t"}}`,
+			`POST /_bulk {"delete":{"_id":"1","_index":"i"}}`,
+			`POST /_msearch {"index":["i"],"type":["t"]}` + "\n{}",
+		},
+	},
+	{
+		ServerVersion{Major: 8, Minor: 11},
+		[]string{
+			"GET /i/_doc/1",
+			`PUT /i/_doc/1/ {"n":1}`,
+			`POST /i/_doc/ {"n":1}`,
+			"DELETE /i/_doc/1/",
+			`POST /i/_update/1 {"doc":{"n":2}}`,
+			"POST /i/_search",
+			"POST /i/_count",
+			`PUT /i/_mapping {"properties":{}}`,
+			"GET /i/_mapping/",
+			"POST /i/_search",
+			`POST /_bulk {"delete":{"_id":"1","_index":"i"}}`,
+			`POST /_bulk {"delete":{"_id":"1","_index":"i"}}`,
+			`POST /_msearch {"index":["i"]}` + "\n{}",
+		},
+	},
+}
+
+func (s *GoesTestSuite) TestTypelessRequests(c *C) {
+	for _, t := range typelessRequests {
+		server := &requestRecorder{}
+		ts := httptest.NewServer(server)
+
+		conn, err := NewClientFromURL(ts.URL)
+		c.Assert(err, IsNil)
+		conn.WithVersion(t.version)
+
+		doc := Document{Index: "i", Type: "t", ID: "1", BulkCommand: BulkCommandDelete, Fields: map[string]interface{}{"n": 1}}
+		_, err = conn.Get("i", "t", "1", nil)
+		c.Assert(err, IsNil)
+		_, err = conn.Index(doc, nil)
+		c.Assert(err, IsNil)
+		_, err = conn.Index(Document{Index: "i", Type: "t", Fields: map[string]interface{}{"n": 1}}, nil)
+		c.Assert(err, IsNil)
+		_, err = conn.Delete(doc, nil)
+		c.Assert(err, IsNil)
+		_, err = conn.Update(doc, map[string]interface{}{"doc": map[string]interface{}{"n": 2}}, nil)
+		c.Assert(err, IsNil)
+		_, err = conn.Search(nil, []string{"i"}, []string{"t"}, nil)
+		c.Assert(err, IsNil)
+		_, err = conn.Count(nil, []string{"i"}, []string{"t"}, nil)
+		c.Assert(err, IsNil)
+		_, err = conn.PutMapping("t", map[string]interface{}{"t": map[string]interface{}{"properties": map[string]interface{}{}}}, []string{"i"})
+		c.Assert(err, IsNil)
+		_, err = conn.GetMapping([]string{"t"}, []string{"i"})
+		c.Assert(err, IsNil)
+		_, err = conn.Scan(nil, []string{"i"}, []string{"t"}, "1m", 10)
+		c.Assert(err, IsNil)
+		_, err = conn.BulkSend([]Document{doc})
+		c.Assert(err, IsNil)
+		// Untyped documents have no _type whatever the version
+		_, err = conn.BulkSend([]Document{{Index: "i", ID: "1", BulkCommand: BulkCommandDelete}})
+		c.Assert(err, IsNil)
+		searches := []MultiSearchEntry{{IndexList: []string{"i"}, TypeList: []string{"t"}}}
+		_, err = conn.MultiSearch(searches, nil)
+		c.Assert(err, IsNil)
+		// The searches are left untouched
+		c.Assert(searches[0].TypeList, DeepEquals, []string{"t"})
+
+		ts.Close()
+		c.Assert(server.requests, DeepEquals, t.expected, Commentf("version %s", t.version))
+	}
+}
+
+func (s *GoesTestSuite) TestTypedRequestsWithoutVersion(c *C) {
+	server := &requestRecorder{}
+	var lock sync.Mutex
+	lookups := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/" {
+			lock.Lock()
+			lookups++
+			lock.Unlock()
+			// The credentials are not allowed to get the version
+			w.WriteHeader(http.StatusForbidden)
+			w.Write([]byte(`{"error":"action [cluster:monitor/main] is unauthorized","status":403}`))
+			return
+		}
+		server.ServeHTTP(w, r)
+	}))
+	defer ts.Close()
+
+	conn, err := NewClientFromURL(ts.URL)
+	c.Assert(err, IsNil)
+	conn.WithRetryPolicy(&RetryPolicy{MaxAttempts: 3, StatusCodes: []uint64{403}})
+
+	doc := Document{Index: "i", Type: "t", ID: "1", BulkCommand: BulkCommandIndex, Fields: map[string]interface{}{"n": 1}}
+	for i := 0; i < 3; i++ {
+		resp, err := conn.Get("i", "t", "1", nil)
+		c.Assert(err, IsNil)
+		c.Assert(resp, NotNil)
+	}
+	_, err = conn.Index(doc, nil)
+	c.Assert(err, IsNil)
+	_, err = conn.Search(nil, []string{"i"}, []string{"t"}, nil)
+	c.Assert(err, IsNil)
+
+	c.Assert(server.requests, DeepEquals, []string{
+		"GET /i/t/1",
+		"GET /i/t/1",
+		"GET /i/t/1",
+		`PUT /i/t/1/ {"n":1}`,
+		"POST /i/t/_search",
+	})
+	// The failed lookup is neither retried nor made again for every request
+	c.Assert(lookups, Equals, 1)
+
+	// It is made again once the delay elapsed
+	conn.versionRetry = time.Now()
+	_, err = conn.Get("i", "t", "1", nil)
+	c.Assert(err, IsNil)
+	c.Assert(lookups, Equals, 2)
+}
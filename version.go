@@ -0,0 +1,164 @@
+package goes
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ServerVersion is the version of the server a client is connected to
+type ServerVersion struct {
+	Major int
+	Minor int
+	Patch int
+
+	// Pre-release suffix of the version, e.g. "SNAPSHOT" for 8.0.0-SNAPSHOT
+	Suffix string
+
+	// Distribution of the server, "opensearch" for OpenSearch and empty for
+	// Elasticsearch
+	Distribution string
+}
+
+// ParseServerVersion parses a version number as returned by the server,
+// e.g. "7.10.2" or "8.0.0-SNAPSHOT"
+func ParseServerVersion(number string) (ServerVersion, error) {
+	var v ServerVersion
+
+	numbers := number
+	if i := strings.IndexByte(number, '-'); i >= 0 {
+		numbers, v.Suffix = number[:i], number[i+1:]
+	}
+
+	parts := strings.Split(numbers, ".")
+	if len(parts) > 3 {
+		return ServerVersion{}, fmt.Errorf("Invalid version %q", number)
+	}
+	for i, field := range []*int{&v.Major, &v.Minor, &v.Patch}[:len(parts)] {
+		n, err := strconv.Atoi(parts[i])
+		if err != nil || n < 0 {
+			return ServerVersion{}, fmt.Errorf("Invalid version %q", number)
+		}
+		*field = n
+	}
+
+	return v, nil
+}
+
+// String returns the version number, e.g. "7.10.2"
+func (v ServerVersion) String() string {
+	number := fmt.Sprintf("%d.%d.%d", v.Major, v.Minor, v.Patch)
+	if v.Suffix != "" {
+		number += "-" + v.Suffix
+	}
+	return number
+}
+
+// Compare returns -1, 0 or 1 when v is lower than, equal to or greater than
+// other. The suffixes and distributions are ignored.
+func (v ServerVersion) Compare(other ServerVersion) int {
+	for _, d := range [...]int{v.Major - other.Major, v.Minor - other.Minor, v.Patch - other.Patch} {
+		if d < 0 {
+			return -1
+		}
+		if d > 0 {
+			return 1
+		}
+	}
+	return 0
+}
+
+// AtLeast returns whether v is major.minor or a later version
+func (v ServerVersion) AtLeast(major, minor int) bool {
+	return v.Compare(ServerVersion{Major: major, Minor: minor}) >= 0
+}
+
+// Before returns whether v is a version older than major.minor
+func (v ServerVersion) Before(major, minor int) bool {
+	return !v.AtLeast(major, minor)
+}
+
+// Capability is a part of the API which depends on the version of the server
+type Capability int
+
+const (
+	// SupportsDeleteByQuery is set when delete by query is available, which
+	// is the case before 2.0 and since 5.0, but not in 2.x where it is a plugin
+	SupportsDeleteByQuery Capability = iota
+
+	// UsesDeleteByQueryAPI is set when delete by query is done with
+	// POST _delete_by_query, since 5.0, instead of DELETE _query
+	UsesDeleteByQueryAPI
+
+	// SupportsDeleteMapping is set when mappings can be deleted, before 2.0
+	SupportsDeleteMapping
+
+	// UsesForceMerge is set when _optimize is named _forcemerge, since 2.1
+	UsesForceMerge
+
+	// ScrollBodyJSON is set when the scroll ids are sent in a JSON body,
+	// since 2.0, instead of the URL or a raw body
+	ScrollBodyJSON
+
+	// ScanSortsByDoc is set when scans sort by _doc, since 5.0, instead of
+	// using search_type=scan
+	ScanSortsByDoc
+
+	// UnprefixedMetadata is set when the metadata of the documents of _bulk
+	// and _mget requests, such as routing, has no leading underscore, since 7.0
+	UnprefixedMetadata
+
+	// Typeless is set when documents have no type, since 7.0
+	Typeless
+
+	// SupportsPointInTime is set when points in time can be opened, since 7.10
+	SupportsPointInTime
+
+	// SupportsSlicedScroll is set when scrolls can be sliced, since 5.0
+	SupportsSlicedScroll
+)
+
+// versionRange is a range of versions from a version included to another
+// one excluded, zero versions leaving the range unbounded
+type versionRange struct {
+	from ServerVersion
+	to   ServerVersion
+}
+
+// capabilities lists the versions of Elasticsearch supporting each capability
+var capabilities = map[Capability][]versionRange{
+	SupportsDeleteByQuery: {{to: ServerVersion{Major: 2}}, {from: ServerVersion{Major: 5}}},
+	UsesDeleteByQueryAPI:  {{from: ServerVersion{Major: 5}}},
+	SupportsDeleteMapping: {{to: ServerVersion{Major: 2}}},
+	UsesForceMerge:        {{from: ServerVersion{Major: 2, Minor: 1}}},
+	ScrollBodyJSON:        {{from: ServerVersion{Major: 2}}},
+	ScanSortsByDoc:        {{from: ServerVersion{Major: 5}}},
+	UnprefixedMetadata:    {{from: ServerVersion{Major: 7}}},
+	Typeless:              {{from: ServerVersion{Major: 7}}},
+	SupportsPointInTime:   {{from: ServerVersion{Major: 7, Minor: 10}}},
+	SupportsSlicedScroll:  {{from: ServerVersion{Major: 5}}},
+}
+
+// OpenSearch has the API of the version of Elasticsearch it was forked
+// from, apart from the capabilities it lacks
+var (
+	openSearchBase        = ServerVersion{Major: 7, Minor: 10, Patch: 2}
+	openSearchUnsupported = map[Capability]bool{SupportsPointInTime: true}
+)
+
+// Supports returns whether a server running v has the given capability
+func (v ServerVersion) Supports(c Capability) bool {
+	if v.Distribution == "opensearch" {
+		if openSearchUnsupported[c] {
+			return false
+		}
+		v = openSearchBase
+	}
+
+	for _, r := range capabilities[c] {
+		if v.Compare(r.from) >= 0 && (r.to == ServerVersion{} || v.Compare(r.to) < 0) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,142 @@
+package goes
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+
+	. "github.com/go-check/check"
+)
+
+func (s *GoesTestSuite) TestParseServerVersion(c *C) {
+	versions := []struct {
+		number  string
+		version ServerVersion
+	}{
+		{"1.7.5", ServerVersion{Major: 1, Minor: 7, Patch: 5}},
+		{"0.90.13", ServerVersion{Major: 0, Minor: 90, Patch: 13}},
+		{"2.10", ServerVersion{Major: 2, Minor: 10}},
+		{"8", ServerVersion{Major: 8}},
+		{"8.0.0-SNAPSHOT", ServerVersion{Major: 8, Suffix: "SNAPSHOT"}},
+		{"7.0.0-alpha1", ServerVersion{Major: 7, Suffix: "alpha1"}},
+	}
+	for _, v := range versions {
+		version, err := ParseServerVersion(v.number)
+		c.Assert(err, IsNil)
+		c.Assert(version, Equals, v.version)
+	}
+	c.Assert(ServerVersion{Major: 8, Suffix: "SNAPSHOT"}.String(), Equals, "8.0.0-SNAPSHOT")
+	c.Assert(ServerVersion{Major: 2, Minor: 10}.String(), Equals, "2.10.0")
+
+	for _, number := range []string{"", "7.x", "1.2.3.4", "-1.0", "v7.10.2"} {
+		_, err := ParseServerVersion(number)
+		c.Assert(err, ErrorMatches, "Invalid version .*", Commentf("version %q", number))
+	}
+}
+
+func (s *GoesTestSuite) TestServerVersionCompare(c *C) {
+	ordered := []string{"0.90.13", "1.7.5", "2.1.0", "2.9.0", "2.10.0", "5.0.0", "7.9.3", "7.10.2", "10.0.0"}
+	for i := range ordered {
+		for j := range ordered {
+			a, _ := ParseServerVersion(ordered[i])
+			b, _ := ParseServerVersion(ordered[j])
+			expected := 0
+			if i < j {
+				expected = -1
+			} else if i > j {
+				expected = 1
+			}
+			c.Assert(a.Compare(b), Equals, expected, Commentf("%s and %s", a, b))
+		}
+	}
+
+	v := ServerVersion{Major: 10}
+	c.Assert(v.AtLeast(5, 0), Equals, true)
+	c.Assert(v.Before(5, 0), Equals, false)
+	v = ServerVersion{Major: 2, Minor: 10}
+	c.Assert(v.AtLeast(2, 9), Equals, true)
+	c.Assert(v.AtLeast(2, 11), Equals, false)
+	c.Assert(v.Before(3, 0), Equals, true)
+	// The suffix is ignored
+	c.Assert(ServerVersion{Major: 8, Suffix: "SNAPSHOT"}.Compare(ServerVersion{Major: 8}), Equals, 0)
+}
+
+func (s *GoesTestSuite) TestServerVersionSupports(c *C) {
+	capabilities := []Capability{
+		SupportsDeleteByQuery, UsesDeleteByQueryAPI, SupportsDeleteMapping, UsesForceMerge,
+		ScrollBodyJSON, ScanSortsByDoc, UnprefixedMetadata, Typeless, SupportsPointInTime,
+		SupportsSlicedScroll,
+	}
+	matrix := []struct {
+		version   ServerVersion
+		supported []bool
+	}{
+		{ServerVersion{Major: 1, Minor: 7, Patch: 5}, []bool{true, false, true, false, false, false, false, false, false, false}},
+		{ServerVersion{Major: 2, Minor: 0}, []bool{false, false, false, false, true, false, false, false, false, false}},
+		{ServerVersion{Major: 2, Minor: 10}, []bool{false, false, false, true, true, false, false, false, false, false}},
+		{ServerVersion{Major: 5, Minor: 6}, []bool{true, true, false, true, true, true, false, false, false, true}},
+		{ServerVersion{Major: 6, Minor: 8}, []bool{true, true, false, true, true, true, false, false, false, true}},
+		{ServerVersion{Major: 7, Minor: 9}, []bool{true, true, false, true, true, true, true, true, false, true}},
+		{ServerVersion{Major: 7, Minor: 10, Patch: 2}, []bool{true, true, false, true, true, true, true, true, true, true}},
+		{ServerVersion{Major: 10}, []bool{true, true, false, true, true, true, true, true, true, true}},
+		{ServerVersion{Major: 2, Minor: 11, Distribution: "opensearch"}, []bool{true, true, false, true, true, true, true, true, false, true}},
+	}
+
+	for _, m := range matrix {
+		for i, capability := range capabilities {
+			c.Assert(m.version.Supports(capability), Equals, m.supported[i], Commentf("capability %d of %s", capability, m.version))
+		}
+	}
+}
+
+func (s *GoesTestSuite) TestClientServerVersion(c *C) {
+	var lock sync.Mutex
+	paths := []string{}
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		lock.Lock()
+		paths = append(paths, r.URL.Path)
+		lock.Unlock()
+		if r.URL.Path == "/" {
+			w.Write([]byte(`{"version":{"distribution":"opensearch","number":"2.11.1"}}`))
+			return
+		}
+		w.Write([]byte(`{}`))
+	}))
+	defer ts.Close()
+
+	conn, err := NewClientFromURL(ts.URL)
+	c.Assert(err, IsNil)
+
+	version, err := conn.ServerVersion()
+	c.Assert(err, IsNil)
+	c.Assert(version, Equals, ServerVersion{Major: 2, Minor: 11, Patch: 1, Distribution: "opensearch"})
+	number, err := conn.Version()
+	c.Assert(err, IsNil)
+	c.Assert(number, Equals, "2.11.1")
+	// The version is cached
+	_, err = conn.ServerVersion()
+	c.Assert(err, IsNil)
+	c.Assert(paths, DeepEquals, []string{"/"})
+
+	// A pinned version is not requested
+	paths = nil
+	conn, err = NewClientFromURL(ts.URL)
+	c.Assert(err, IsNil)
+	conn.WithVersion(ServerVersion{Major: 1, Minor: 7})
+	_, err = conn.Optimize([]string{"i"}, url.Values{})
+	c.Assert(err, IsNil)
+	_, err = conn.DeleteMapping("t", []string{"i"})
+	c.Assert(err, IsNil)
+	c.Assert(paths, DeepEquals, []string{"/i/_optimize", "/i/_mappings/t"})
+	number, err = conn.Version()
+	c.Assert(err, IsNil)
+	c.Assert(number, Equals, "1.7.0")
+
+	conn.WithVersion(ServerVersion{Major: 10})
+	_, err = conn.Optimize([]string{"i"}, url.Values{})
+	c.Assert(err, IsNil)
+	_, err = conn.DeleteMapping("t", []string{"i"})
+	c.Assert(err, ErrorMatches, "Deletion of mappings is not supported .*")
+	c.Assert(paths, DeepEquals, []string{"/i/_optimize", "/i/_mappings/t", "/i/_forcemerge"})
+}